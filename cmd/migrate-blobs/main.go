@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/joho/godotenv"
+
+	"github.com/debate-io/service-auth/internal/app"
+	"github.com/debate-io/service-auth/internal/infrastructure/persistence/postgres"
+)
+
+// Переносит файлы изображений из таблиц Postgres в хранилище, заданное BLOB_BACKEND.
+func main() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Println(err)
+	}
+
+	config, err := app.GetAppConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := app.NewLogger(config.IsDebug)
+	db, err := postgres.NewPostgresDatabase(config.PostgresDsn, config.ServiceName, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	moved, err := app.MigrateImageBlobs(context.Background(), config, db)
+	if err != nil {
+		log.Fatalf("перенесено файлов: %d, ошибка: %v", moved, err)
+	}
+
+	log.Printf("перенесено файлов: %d", moved)
+}
@@ -4,6 +4,7 @@ go 1.23
 
 require (
 	github.com/99designs/gqlgen v0.17.61
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/go-chi/chi v1.5.4
 	github.com/go-chi/cors v1.2.0
 	github.com/go-chi/render v1.0.1
@@ -12,13 +13,15 @@ require (
 	github.com/go-playground/validator/v10 v10.10.1
 	github.com/golang-jwt/jwt/v4 v4.4.2
 	github.com/golang-migrate/migrate/v4 v4.18.1
+	github.com/gorilla/websocket v1.5.0
+	github.com/hashicorp/golang-lru/v2 v2.0.7
 	github.com/inhies/go-bytesize v0.0.0-20210819104631-275770b98743
 	github.com/joho/godotenv v1.4.0
 	github.com/vektah/gqlparser/v2 v2.5.20
-	github.com/whadron/go-murmurhash3 v0.0.0-20160918122457-1e729dda1559
 	github.com/ztrue/tracerr v0.3.0
 	go.uber.org/zap v1.21.0
 	golang.org/x/crypto v0.27.0
+	golang.org/x/image v0.23.0
 	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df
 	gopkg.in/tylerb/graceful.v1 v1.2.15
 )
@@ -34,10 +37,8 @@ require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
-	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/leodido/go-urn v1.2.1 // indirect
 	github.com/lib/pq v1.10.9 // indirect
@@ -1,11 +1,18 @@
 package app
 
 import (
+	"context"
 	"os"
 	"time"
 
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/debate-io/service-auth/internal/infrastructure/auth"
-	"github.com/debate-io/service-auth/internal/infrastructure/smtp"
+	"github.com/debate-io/service-auth/internal/infrastructure/imageurl"
+	"github.com/debate-io/service-auth/internal/infrastructure/imaging"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailtemplate"
+	"github.com/debate-io/service-auth/internal/infrastructure/password"
+	"github.com/debate-io/service-auth/internal/infrastructure/unsubscribe"
 
 	pg "github.com/go-pg/pg/v9"
 	"go.uber.org/zap"
@@ -13,16 +20,30 @@ import (
 
 	"github.com/debate-io/service-auth/internal/infrastructure/persistence/postgres"
 	"github.com/debate-io/service-auth/internal/interface/server"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
 	"github.com/debate-io/service-auth/internal/registry"
 	"github.com/debate-io/service-auth/internal/usecases"
 )
 
+// eventBufferSize — сколько событий подписки может ждать отправки, прежде чем новые начнут теряться
+const eventBufferSize = 16
+
+// Каналы Postgres, через которые экземпляры сервиса обмениваются событиями подписок
+const (
+	gameEventsChannel         = "game_events"
+	notificationEventsChannel = "notification_events"
+)
+
 type App struct {
-	Logger     *zap.Logger
-	Server     *server.Server
-	DB         *pg.DB
-	SmtpSender *smtp.Sender
-	Config     *Config
+	Logger *zap.Logger
+	Server *server.Server
+	DB     *pg.DB
+	Mailer repo.Mailer
+	Config *Config
+
+	stopWorkers func()
+	// listeners получают события подписок от всех экземпляров сервиса, запускаются вместе с фоновыми задачами
+	listeners []func(ctx context.Context)
 }
 
 func NewApp(config *Config) *App {
@@ -37,24 +58,17 @@ func NewApp(config *Config) *App {
 		logger.Fatal("can't connect to postgres database", zap.Error(err))
 	}
 
-	smtpClient, err := smtp.NewSender(&smtp.Config{
-		Host:     config.Smtp.Host,
-		Port:     config.Smtp.Port,
-		Username: config.Smtp.Username,
-		Password: config.Smtp.Password,
-		SSL:      config.Smtp.SSL,
-		From:     config.Smtp.From,
-	})
+	mailer, err := NewMailer(config, logger)
 	if err != nil {
-		logger.Error("can't connect to SMTP server", zap.Error(err))
+		logger.Fatal("can't initialize mailer", zap.Error(err))
 	}
 
 	return &App{
-		Logger:     logger,
-		Server:     server.NewServer(logger),
-		DB:         db,
-		SmtpSender: smtpClient,
-		Config:     config,
+		Logger: logger,
+		Server: server.NewServer(logger),
+		DB:     db,
+		Mailer: mailer,
+		Config: config,
 	}
 }
 
@@ -89,19 +103,30 @@ func (app *App) RunApp() {
 func (app *App) Initialize() {
 	authService := auth.NewAuthService(
 		auth.Config{
-			JwtSecretAuth:       app.Config.Jwt.JwtSecretAuth,
-			JwtSecretMessages:   app.Config.Jwt.JwtSecretMessages,
-			DaysAuthExpires:     app.Config.Jwt.DaysAuthExpires,
-			DaysRecoveryExpires: app.Config.Jwt.DaysRecoveryExpires,
+			JwtSecretAuth:               app.Config.Jwt.JwtSecretAuth,
+			JwtSecretMessages:           app.Config.Jwt.JwtSecretMessages,
+			DaysAuthExpires:             app.Config.Jwt.DaysAuthExpires,
+			DaysRecoveryExpires:         app.Config.Jwt.DaysRecoveryExpires,
+			MinutesImpersonationExpires: app.Config.Jwt.MinutesImpersonationExpires,
 		},
 	)
 
+	trustedProxies, err := middleware.ParseTrustedProxies(app.Config.TrustedProxies)
+	if err != nil {
+		app.Logger.Fatal("can't parse trusted proxies", zap.Error(err))
+	}
+
 	container := app.NewContainer(authService)
-	app.Server.InitMiddlewares(app.Config.IsDebug, authService)
-	app.Server.InitRoutes(container, app.Config.IsDebug)
+	app.Server.InitMiddlewares(app.Config.IsDebug, authService, container.UseCases.Users, trustedProxies)
+	app.Server.InitRoutes(container, authService, app.Config.IsDebug)
+	app.StartWorkers(container)
 }
 
 func (app *App) beforeShutdown() {
+	if app.stopWorkers != nil {
+		app.stopWorkers()
+	}
+
 	if !app.Config.IsDebug {
 		const shutdownIdle = 9 * time.Second
 
@@ -115,16 +140,114 @@ func (app *App) beforeShutdown() {
 func (app *App) NewContainer(authService *auth.AuthService) *registry.Container {
 	userRepo := postgres.NewUserRepository(app.DB)
 	recoveryCodeRepo := postgres.NewRecoveryCodeRepository(app.DB)
+	emailChangeRepo := postgres.NewEmailChangeRepository(app.DB)
 	gameStatsRepository := postgres.NewGameStatsRepository(app.DB)
 	achievementRepository := postgres.NewAchievementRepository(app.DB)
-	gameRepository := postgres.NewGameRepository(app.DB)
+	gameEvents := postgres.NewNotifyBus[model.GameStatus](app.DB, gameEventsChannel, eventBufferSize, app.Logger)
+	notificationEvents := postgres.NewNotifyBus[model.Notification](app.DB, notificationEventsChannel, eventBufferSize, app.Logger)
+	app.listeners = append(app.listeners, gameEvents.Listen, notificationEvents.Listen)
+
+	gameRepository := postgres.NewGameRepository(app.DB, gameEvents, app.NewResultPolicy())
+	reportRepository := postgres.NewReportRepository(app.DB)
+	auditRepository := postgres.NewAuditRepository(app.DB)
+	emailOutboxRepository := postgres.NewEmailOutboxRepository(app.DB)
+	notificationPreferenceRepository := postgres.NewNotificationPreferenceRepository(app.DB)
+	notificationRepository := postgres.NewNotificationRepository(app.DB)
 
 	topicRepo := postgres.NewTopicRepository(app.DB)
 
+	audit := usecases.NewAuditUseCase(auditRepository, app.Logger)
+
+	blobStore, err := NewBlobStore(app.Config, app.DB)
+	if err != nil {
+		app.Logger.Fatal("can't initialize blob store", zap.Error(err))
+	}
+
+	passwordPolicy, err := password.NewPolicy(password.PolicyConfig{
+		MinLength:          app.Config.Password.MinLength,
+		BreachedCorpusFile: app.Config.Password.BreachedCorpusFile,
+	})
+	if err != nil {
+		app.Logger.Fatal("can't initialize password policy", zap.Error(err))
+	}
+
+	passwordHasher, err := password.NewHasher(password.HasherConfig{
+		Algorithm: password.Algorithm(app.Config.Password.HashAlgorithm),
+		Argon2id: password.Argon2idParams{
+			MemoryKiB:   uint32(app.Config.Password.Argon2MemoryKiB),
+			Iterations:  uint32(app.Config.Password.Argon2Iterations),
+			Parallelism: uint8(app.Config.Password.Argon2Parallelism),
+		},
+		BcryptCost: app.Config.Password.BcryptCost,
+	})
+	if err != nil {
+		app.Logger.Fatal("can't initialize password hasher", zap.Error(err))
+	}
+
+	mailRenderer, err := mailtemplate.NewRenderer(mailtemplate.Branding{
+		ProductName:  app.Config.Mail.ProductName,
+		BaseURL:      app.Config.PublicBaseUrl,
+		SupportEmail: app.Config.Mail.SupportEmail,
+	}, app.Config.Mail.DefaultLocale)
+	if err != nil {
+		app.Logger.Fatal("can't initialize mail templates", zap.Error(err))
+	}
+
+	imageURLs := imageurl.NewBuilder(imageurl.Config{
+		BaseURL: app.Config.ImageURL.BaseUrl,
+		Secret:  app.Config.ImageURL.Secret,
+		TTL:     time.Duration(app.Config.ImageURL.TTLMinutes) * time.Minute,
+	})
+
+	mail := usecases.NewMailUseCase(emailOutboxRepository, notificationPreferenceRepository, app.Mailer, mailRenderer,
+		unsubscribe.NewSigner(app.Config.Mail.UnsubscribeSecret), app.Config.PublicBaseUrl, app.Config.Mail.MaxAttempts,
+		time.Duration(app.Config.Mail.RetentionHours)*time.Hour)
+	notifications := usecases.NewNotificationUseCase(notificationRepository, userRepo, mail, notificationEvents, app.Logger)
+
+	bans := usecases.NewBanCache()
+
+	games := usecases.NewGameUseCase(gameRepository, achievementRepository, userRepo, notifications, gameEvents,
+		time.Duration(app.Config.Game.RetentionMinutes)*time.Minute, app.Logger)
+
 	useCases := &registry.UseCases{
-		Users:  usecases.NewUserUseCases(userRepo, recoveryCodeRepo, gameStatsRepository, achievementRepository, app.SmtpSender, authService),
-		Topics: usecases.NewTopicUseCase(topicRepo),
-		Games:  usecases.NewGameUseCase(gameRepository),
+		Users: usecases.NewUserUseCases(
+			usecases.UserRepos{
+				Users:         userRepo,
+				RecoveryCodes: recoveryCodeRepo,
+				EmailChanges:  emailChangeRepo,
+				GameStats:     gameStatsRepository,
+				Achievements:  achievementRepository,
+			},
+			usecases.UserMail{
+				Mail:          mail,
+				PublicBaseUrl: app.Config.PublicBaseUrl,
+			},
+			usecases.UserPasswords{
+				Policy: passwordPolicy,
+				Hasher: passwordHasher,
+			},
+			usecases.UserImages{
+				Cache:         usecases.NewImageCache(app.Config.ImageCacheSize),
+				BlobStore:     blobStore,
+				URLs:          imageURLs,
+				DefaultAvatar: app.Config.ImageURL.DefaultStyle,
+				Limits: imaging.Limits{
+					MaxBytes:     int64(app.Config.ImageUpload.MaxBytes),
+					MaxDimension: app.Config.ImageUpload.MaxDimension,
+					Formats:      app.Config.ImageUpload.Formats,
+				},
+			},
+			bans,
+			authService,
+			audit,
+			app.Logger,
+		),
+		Topics:        usecases.NewTopicUseCase(topicRepo, audit, notifications),
+		Games:         games,
+		Reports:       usecases.NewReportUseCase(reportRepository, gameRepository, bans, audit),
+		Audit:         audit,
+		Mail:          mail,
+		Notifications: notifications,
 	}
 
 	return &registry.Container{UseCases: useCases, Logger: app.Logger}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/blob"
+	"github.com/debate-io/service-auth/internal/infrastructure/persistence/postgres"
+	"github.com/debate-io/service-auth/internal/usecases"
+	"github.com/go-pg/pg/v9"
+)
+
+const blobMigrationBatchSize = 100
+
+// NewBlobStore создаёт хранилище файлов, выбранное в BLOB_BACKEND.
+func NewBlobStore(config *Config, db *pg.DB) (repo.BlobStore, error) {
+	switch config.Blob.Backend {
+	case "fs":
+		return blob.NewFSStore(config.Blob.FSRoot)
+	case "s3":
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:  config.Blob.S3Endpoint,
+			Region:    config.Blob.S3Region,
+			Bucket:    config.Blob.S3Bucket,
+			AccessKey: config.Blob.S3AccessKey,
+			SecretKey: config.Blob.S3SecretKey,
+		})
+	default:
+		return postgres.NewBlobStore(db), nil
+	}
+}
+
+// MigrateImageBlobs переносит содержимое images.file и image_variants.file в настроенное хранилище.
+// Нужны только база и хранилище, поэтому приложение целиком не собирается.
+func MigrateImageBlobs(ctx context.Context, config *Config, db *pg.DB) (int, error) {
+	blobStore, err := NewBlobStore(config, db)
+	if err != nil {
+		return 0, err
+	}
+
+	return usecases.MigrateImagesToBlobStore(ctx, postgres.NewUserRepository(db), blobStore, blobMigrationBatchSize)
+}
@@ -3,17 +3,59 @@ package app
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
+const (
+	defaultMinutesImpersonationExpires = 15
+	defaultPasswordMinLength           = 8
+	defaultArgon2MemoryKiB             = 64 * 1024
+	defaultArgon2Iterations            = 3
+	defaultArgon2Parallelism           = 2
+	defaultBcryptCost                  = 10
+	defaultPublicBaseUrl               = "http://localhost:9090"
+	defaultImageGCIntervalMinutes      = 60
+	defaultImageCacheSize              = 256
+	defaultImageURLTTLMinutes          = 24 * 60
+	defaultImageMaxBytes               = 5 * 1024 * 1024
+	defaultImageMaxDimension           = 4096
+	defaultImageFormats                = "jpeg,png,webp"
+	defaultMailProductName             = "Debate"
+	defaultMailFrom                    = "noreply@localhost"
+	defaultLocale                      = "ru"
+	defaultEmailMaxAttempts            = 8
+	defaultEmailOutboxIntervalSeconds  = 10
+	defaultEmailRetentionHours         = 7 * 24
+	defaultGameReaperIntervalSeconds   = 5
+	defaultGameRetentionMinutes        = 10
+	defaultGameDrawThresholdSeconds    = 4
+)
+
 type Config struct {
 	ServiceName string `validate:"required"`
 	PostgresDsn string `validate:"required"`
 	Address     string `validate:"required"`
 	IsDebug     bool   `validate:"omitempty"`
-	Smtp        SmtpConfig
+	// PublicBaseUrl — внешний адрес сервиса, используется в ссылках из писем.
+	PublicBaseUrl string `validate:"required,url"`
+	// ImageGCIntervalMinutes — как часто удалять изображения, на которые никто не ссылается
+	ImageGCIntervalMinutes int `validate:"min=1"`
+	// ImageCacheSize — сколько аватаров держать в памяти, 0 отключает кеш
+	ImageCacheSize int `validate:"min=0"`
+	// TrustedProxies — сети прокси в нотации CIDR, которым можно верить в X-Forwarded-For и X-Real-IP.
+	// Пусто — адрес клиента берётся только из соединения.
+	TrustedProxies []string `validate:"dive,cidr"`
+	// Smtp проверяется, только если письма отправляются через SMTP
+	Smtp        SmtpConfig `validate:"-"`
+	Mail        MailConfig
+	Game        GameConfig
 	Jwt         jwtConfig
+	Password    PasswordConfig
+	Blob        BlobConfig
+	ImageURL    ImageURLConfig
+	ImageUpload ImageUploadConfig
 }
 
 type SmtpConfig struct {
@@ -21,19 +63,97 @@ type SmtpConfig struct {
 	Port     int    `validate:"required"`
 	Username string `validate:"required"`
 	Password string `validate:"required"`
-	From     string `validate:"required"`
 	SSL      bool
 }
 
+// MailConfig описывает отправку и оформление писем.
+type MailConfig struct {
+	// Backend — куда отправлять письма: smtp, eml (файлы в EMLDir), log или memory
+	Backend      string `validate:"oneof=smtp eml log memory"`
+	EMLDir       string `validate:"required_if=Backend eml"`
+	From         string `validate:"required"`
+	ProductName  string `validate:"required"`
+	SupportEmail string `validate:"omitempty,email"`
+	// DefaultLocale — язык писем для пользователей, у которых он не указан или не поддерживается
+	DefaultLocale string `validate:"oneof=ru en"`
+	// UnsubscribeSecret подписывает ссылки отписки, по умолчанию JWT_SECRET_MESSAGES
+	UnsubscribeSecret string `validate:"required"`
+	// MaxAttempts — после стольких неудачных попыток письмо больше не отправляется
+	MaxAttempts int `validate:"min=1"`
+	// OutboxIntervalSeconds — как часто воркер проверяет очередь писем
+	OutboxIntervalSeconds int `validate:"min=1"`
+	// RetentionHours — через сколько после создания стираются тела отправленных и недоставленных писем
+	RetentionHours int `validate:"min=1"`
+}
+
+type GameConfig struct {
+	// ReaperIntervalSeconds — как часто завершать игры с истёкшим дедлайном
+	ReaperIntervalSeconds int `validate:"min=1"`
+	// RetentionMinutes — сколько закончившаяся игра хранится в live_games для запросов статуса
+	RetentionMinutes int `validate:"min=1"`
+	// ResultPolicy — как решается итог: score (по времени в игре) или judged (судьёй)
+	ResultPolicy string `validate:"oneof=score judged"`
+	// DrawThresholdSeconds — разница во времени в игре, при которой policy score объявляет ничью
+	DrawThresholdSeconds int `validate:"min=0"`
+}
+
+type PasswordConfig struct {
+	MinLength          int `validate:"min=1"`
+	BreachedCorpusFile string
+	HashAlgorithm      string `validate:"oneof=argon2id bcrypt"`
+	Argon2MemoryKiB    int    `validate:"min=8"`
+	Argon2Iterations   int    `validate:"min=1"`
+	Argon2Parallelism  int    `validate:"min=1,max=255"`
+	BcryptCost         int    `validate:"min=4,max=31"`
+}
+
+// ImageUploadConfig ограничивает загружаемые аватары.
+type ImageUploadConfig struct {
+	MaxBytes     int      `validate:"min=1"`
+	MaxDimension int      `validate:"min=1"`
+	Formats      []string `validate:"min=1,dive,oneof=jpeg png gif webp"`
+}
+
+// ImageURLConfig описывает ссылки на аватары, которые сервис отдаёт клиентам.
+type ImageURLConfig struct {
+	// BaseUrl — адрес CDN или самого сервиса, по умолчанию PublicBaseUrl
+	BaseUrl string `validate:"required,url"`
+	// Secret включает подписанные ссылки с ограниченным сроком действия
+	Secret     string
+	TTLMinutes int `validate:"min=1"`
+	// DefaultStyle — как рисовать аватар пользователям без изображения: identicon или initials
+	DefaultStyle string `validate:"oneof=identicon initials"`
+}
+
+// BlobConfig описывает хранилище файлов изображений: postgres, fs или s3.
+type BlobConfig struct {
+	Backend     string `validate:"oneof=postgres fs s3"`
+	FSRoot      string `validate:"required_if=Backend fs"`
+	S3Endpoint  string `validate:"required_if=Backend s3"`
+	S3Region    string `validate:"required_if=Backend s3"`
+	S3Bucket    string `validate:"required_if=Backend s3"`
+	S3AccessKey string `validate:"required_if=Backend s3"`
+	S3SecretKey string `validate:"required_if=Backend s3"`
+}
+
 type jwtConfig struct {
-	JwtSecretAuth       string `validate:"required"`
-	JwtSecretMessages   string `validate:"required"`
-	DaysAuthExpires     int    `validate:"required"`
-	DaysRecoveryExpires int    `validate:"required"`
+	JwtSecretAuth               string `validate:"required"`
+	JwtSecretMessages           string `validate:"required"`
+	DaysAuthExpires             int    `validate:"required"`
+	DaysRecoveryExpires         int    `validate:"required"`
+	MinutesImpersonationExpires int    `validate:"required"`
 }
 
 func (c Config) Validate() error {
-	return validator.New().Struct(c)
+	validate := validator.New()
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+	if c.Mail.Backend == "smtp" {
+		return validate.Struct(c.Smtp)
+	}
+
+	return nil
 }
 
 func GetAppConfig() (*Config, error) {
@@ -47,29 +167,167 @@ func GetAppConfig() (*Config, error) {
 		return nil, err
 	}
 
-	smtpPort, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	smtpPort, err := getEnvInt("SMTP_PORT", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	minutesImpersonationExpires, err := getEnvInt("MINUTES_IMPERSONATION_EXPIRES", defaultMinutesImpersonationExpires)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordMinLength, err := getEnvInt("PASSWORD_MIN_LENGTH", defaultPasswordMinLength)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2MemoryKiB, err := getEnvInt("ARGON2_MEMORY_KIB", defaultArgon2MemoryKiB)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2Iterations, err := getEnvInt("ARGON2_ITERATIONS", defaultArgon2Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2Parallelism, err := getEnvInt("ARGON2_PARALLELISM", defaultArgon2Parallelism)
+	if err != nil {
+		return nil, err
+	}
+
+	bcryptCost, err := getEnvInt("BCRYPT_COST", defaultBcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	imageGCIntervalMinutes, err := getEnvInt("IMAGE_GC_INTERVAL_MINUTES", defaultImageGCIntervalMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	imageCacheSize, err := getEnvInt("IMAGE_CACHE_SIZE", defaultImageCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	imageURLTTLMinutes, err := getEnvInt("IMAGE_URL_TTL_MINUTES", defaultImageURLTTLMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	imageMaxBytes, err := getEnvInt("IMAGE_MAX_BYTES", defaultImageMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	imageMaxDimension, err := getEnvInt("IMAGE_MAX_DIMENSION", defaultImageMaxDimension)
+	if err != nil {
+		return nil, err
+	}
+
+	emailMaxAttempts, err := getEnvInt("EMAIL_MAX_ATTEMPTS", defaultEmailMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	emailOutboxIntervalSeconds, err := getEnvInt("EMAIL_OUTBOX_INTERVAL_SECONDS", defaultEmailOutboxIntervalSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	emailRetentionHours, err := getEnvInt("EMAIL_RETENTION_HOURS", defaultEmailRetentionHours)
+	if err != nil {
+		return nil, err
+	}
+
+	gameReaperIntervalSeconds, err := getEnvInt("GAME_REAPER_INTERVAL_SECONDS", defaultGameReaperIntervalSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	gameRetentionMinutes, err := getEnvInt("GAME_RETENTION_MINUTES", defaultGameRetentionMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	gameDrawThresholdSeconds, err := getEnvInt("GAME_DRAW_THRESHOLD_SECONDS", defaultGameDrawThresholdSeconds)
 	if err != nil {
 		return nil, err
 	}
 
+	publicBaseUrl := getEnvString("PUBLIC_BASE_URL", defaultPublicBaseUrl)
+
 	config := &Config{
-		ServiceName: os.Getenv("SERVICE_NAME"),
-		PostgresDsn: os.Getenv("POSTGRES_DSN"),
-		Address:     os.Getenv("SERVER_ADDRESS"),
-		IsDebug:     os.Getenv("IS_DEBUG") == "true",
+		ServiceName:            os.Getenv("SERVICE_NAME"),
+		PostgresDsn:            os.Getenv("POSTGRES_DSN"),
+		Address:                os.Getenv("SERVER_ADDRESS"),
+		IsDebug:                os.Getenv("IS_DEBUG") == "true",
+		PublicBaseUrl:          publicBaseUrl,
+		ImageGCIntervalMinutes: imageGCIntervalMinutes,
+		ImageCacheSize:         imageCacheSize,
+		TrustedProxies:         splitList(os.Getenv("TRUSTED_PROXIES")),
 		Smtp: SmtpConfig{
 			Host:     os.Getenv("SMTP_HOST"),
 			Port:     smtpPort,
 			Username: os.Getenv("SMTP_USERNAME"),
 			Password: os.Getenv("SMTP_PASSWORD"),
-			From:     os.Getenv("SMTP_FROM"),
 			SSL:      os.Getenv("SMTP_SSL") == "true",
 		},
+		Mail: MailConfig{
+			Backend:               getEnvString("MAIL_BACKEND", "smtp"),
+			EMLDir:                os.Getenv("MAIL_EML_DIR"),
+			From:                  getEnvString("MAIL_FROM", getEnvString("SMTP_FROM", defaultMailFrom)),
+			ProductName:           getEnvString("MAIL_PRODUCT_NAME", defaultMailProductName),
+			SupportEmail:          os.Getenv("MAIL_SUPPORT_EMAIL"),
+			DefaultLocale:         getEnvString("DEFAULT_LOCALE", defaultLocale),
+			UnsubscribeSecret:     getEnvString("UNSUBSCRIBE_SECRET", os.Getenv("JWT_SECRET_MESSAGES")),
+			MaxAttempts:           emailMaxAttempts,
+			OutboxIntervalSeconds: emailOutboxIntervalSeconds,
+			RetentionHours:        emailRetentionHours,
+		},
+		Game: GameConfig{
+			ReaperIntervalSeconds: gameReaperIntervalSeconds,
+			RetentionMinutes:      gameRetentionMinutes,
+			ResultPolicy:          getEnvString("GAME_RESULT_POLICY", "score"),
+			DrawThresholdSeconds:  gameDrawThresholdSeconds,
+		},
 		Jwt: jwtConfig{
-			JwtSecretAuth:       os.Getenv("JWT_SECRET_AUTH"),
-			JwtSecretMessages:   os.Getenv("JWT_SECRET_MESSAGES"),
-			DaysAuthExpires:     daysAuthExpires,
-			DaysRecoveryExpires: daysRecoveryExpires,
+			JwtSecretAuth:               os.Getenv("JWT_SECRET_AUTH"),
+			JwtSecretMessages:           os.Getenv("JWT_SECRET_MESSAGES"),
+			DaysAuthExpires:             daysAuthExpires,
+			DaysRecoveryExpires:         daysRecoveryExpires,
+			MinutesImpersonationExpires: minutesImpersonationExpires,
+		},
+		Password: PasswordConfig{
+			MinLength:          passwordMinLength,
+			BreachedCorpusFile: os.Getenv("PASSWORD_BREACHED_CORPUS_FILE"),
+			HashAlgorithm:      getEnvString("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			Argon2MemoryKiB:    argon2MemoryKiB,
+			Argon2Iterations:   argon2Iterations,
+			Argon2Parallelism:  argon2Parallelism,
+			BcryptCost:         bcryptCost,
+		},
+		ImageURL: ImageURLConfig{
+			BaseUrl:      getEnvString("IMAGE_BASE_URL", publicBaseUrl),
+			Secret:       os.Getenv("IMAGE_URL_SECRET"),
+			TTLMinutes:   imageURLTTLMinutes,
+			DefaultStyle: getEnvString("DEFAULT_AVATAR_STYLE", "identicon"),
+		},
+		ImageUpload: ImageUploadConfig{
+			MaxBytes:     imageMaxBytes,
+			MaxDimension: imageMaxDimension,
+			Formats:      strings.Split(getEnvString("IMAGE_ALLOWED_FORMATS", defaultImageFormats), ","),
+		},
+		Blob: BlobConfig{
+			Backend:     getEnvString("BLOB_BACKEND", "postgres"),
+			FSRoot:      os.Getenv("BLOB_FS_ROOT"),
+			S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+			S3Region:    getEnvString("S3_REGION", "us-east-1"),
+			S3Bucket:    os.Getenv("S3_BUCKET"),
+			S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			S3SecretKey: os.Getenv("S3_SECRET_KEY"),
 		},
 	}
 
@@ -79,3 +337,33 @@ func GetAppConfig() (*Config, error) {
 
 	return config, nil
 }
+
+// getEnvInt читает необязательную числовую переменную окружения.
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func getEnvString(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+// splitList разбирает список через запятую, пустые элементы пропускаются.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
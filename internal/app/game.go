@@ -0,0 +1,18 @@
+package app
+
+import (
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/gameresult"
+)
+
+// NewResultPolicy создаёт правило подведения итогов игр, выбранное в GAME_RESULT_POLICY.
+func (app *App) NewResultPolicy() repo.ResultPolicy {
+	switch app.Config.Game.ResultPolicy {
+	case "judged":
+		return gameresult.NewJudgedPolicy()
+	default:
+		return gameresult.NewScorePolicy(time.Duration(app.Config.Game.DrawThresholdSeconds) * time.Second)
+	}
+}
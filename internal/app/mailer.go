@@ -0,0 +1,35 @@
+package app
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailer"
+	"github.com/debate-io/service-auth/internal/infrastructure/smtp"
+)
+
+// NewMailer создаёт отправителя писем, выбранного в MAIL_BACKEND.
+func NewMailer(config *Config, logger *zap.Logger) (repo.Mailer, error) {
+	switch config.Mail.Backend {
+	case "eml":
+		return mailer.NewEMLMailer(config.Mail.EMLDir, config.Mail.From)
+	case "log":
+		return mailer.NewLogMailer(logger), nil
+	case "memory":
+		return mailer.NewMemoryMailer(), nil
+	default:
+		sender := smtp.NewSender(&smtp.Config{
+			Host:     config.Smtp.Host,
+			Port:     config.Smtp.Port,
+			Username: config.Smtp.Username,
+			Password: config.Smtp.Password,
+			SSL:      config.Smtp.SSL,
+			From:     config.Mail.From,
+		})
+		if err := sender.Ping(); err != nil {
+			logger.Warn("SMTP server is unavailable, emails will wait in outbox", zap.Error(err))
+		}
+
+		return sender, nil
+	}
+}
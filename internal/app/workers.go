@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/debate-io/service-auth/internal/registry"
+)
+
+// emailRetentionInterval — как часто стирать тела старых писем. Срок хранения измеряется часами,
+// чаще проверять незачем.
+const emailRetentionInterval = time.Hour
+
+// StartWorkers запускает фоновые задачи. Они останавливаются перед завершением сервера.
+func (app *App) StartWorkers(container *registry.Container) {
+	ctx, cancel := context.WithCancel(context.Background())
+	app.stopWorkers = cancel
+
+	for _, listen := range app.listeners {
+		go listen(ctx)
+	}
+
+	go app.runPeriodically(ctx, "image-gc", time.Duration(app.Config.ImageGCIntervalMinutes)*time.Minute,
+		func(ctx context.Context) error {
+			deleted, err := container.UseCases.Users.CollectUnusedImages(ctx)
+			if deleted > 0 {
+				app.Logger.Info("удалены неиспользуемые изображения", zap.Int("count", deleted))
+			}
+			return err
+		},
+	)
+
+	go app.runPeriodically(ctx, "email-outbox", time.Duration(app.Config.Mail.OutboxIntervalSeconds)*time.Second,
+		func(ctx context.Context) error {
+			sent, dead, err := container.UseCases.Mail.DeliverPendingEmails(ctx)
+			if sent > 0 {
+				app.Logger.Info("отправлены письма", zap.Int("count", sent))
+			}
+			if dead > 0 {
+				app.Logger.Warn("письма не доставлены после всех попыток", zap.Int("count", dead))
+			}
+			return err
+		},
+	)
+
+	go app.runPeriodically(ctx, "email-retention", emailRetentionInterval,
+		func(ctx context.Context) error {
+			redacted, err := container.UseCases.Mail.RedactDeliveredEmails(ctx)
+			if redacted > 0 {
+				app.Logger.Info("стёрты тела старых писем", zap.Int("count", redacted))
+			}
+			return err
+		},
+	)
+
+	go app.runPeriodically(ctx, "game-reaper", time.Duration(app.Config.Game.ReaperIntervalSeconds)*time.Second,
+		func(ctx context.Context) error {
+			expired, evicted, err := container.UseCases.Games.ReapGames(ctx)
+			if expired > 0 || evicted > 0 {
+				app.Logger.Info("обработаны игры с истёкшим дедлайном",
+					zap.Int("expired", expired),
+					zap.Int("evicted", evicted),
+				)
+			}
+			return err
+		},
+	)
+}
+
+func (app *App) runPeriodically(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil && ctx.Err() == nil {
+				app.Logger.Error("background job failed", zap.String("job", name), zap.Error(err))
+			}
+		}
+	}
+}
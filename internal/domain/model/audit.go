@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+type AuditActionEnum string
+
+const (
+	AuditActionUserUpdated              AuditActionEnum = "USER_UPDATED"
+	AuditActionUserEmailChanged         AuditActionEnum = "USER_EMAIL_CHANGED"
+	AuditActionUserEmailChangeRequested AuditActionEnum = "USER_EMAIL_CHANGE_REQUESTED"
+	AuditActionUserEmailChangeReverted  AuditActionEnum = "USER_EMAIL_CHANGE_REVERTED"
+	AuditActionUserPasswordChanged      AuditActionEnum = "USER_PASSWORD_CHANGED"
+	AuditActionUserPasswordRecovery     AuditActionEnum = "USER_PASSWORD_RECOVERY_REQUESTED"
+	AuditActionUserPasswordReset        AuditActionEnum = "USER_PASSWORD_RESET"
+	AuditActionTopicUpdated             AuditActionEnum = "TOPIC_UPDATED"
+	AuditActionReportResolved           AuditActionEnum = "REPORT_RESOLVED"
+	AuditActionUserAvatarChanged        AuditActionEnum = "USER_AVATAR_CHANGED"
+	AuditActionUserImpersonated         AuditActionEnum = "USER_IMPERSONATED"
+	// AuditActionImpersonatedRequest пишется на каждый запрос, выполненный по токену имперсонации.
+	AuditActionImpersonatedRequest AuditActionEnum = "IMPERSONATED_REQUEST"
+)
+
+type AuditTargetEnum string
+
+const (
+	AuditTargetUser   AuditTargetEnum = "USER"
+	AuditTargetTopic  AuditTargetEnum = "TOPIC"
+	AuditTargetReport AuditTargetEnum = "REPORT"
+)
+
+// AuditEvent - неизменяемая запись журнала аудита. Таблица audit_events
+// защищена триггером от UPDATE и DELETE.
+type AuditEvent struct {
+	tableName struct{}  `pg:"audit_events,alias:ae"`
+	ID        int       `pg:"id,pk"`
+	ActorID   *int      `pg:"actor_id"`
+	ActorRole *RoleEnum `pg:"actor_role, type:role_enum"`
+	// ImpersonatorID - администратор, который действовал от имени ActorID.
+	ImpersonatorID *int                   `pg:"impersonator_id"`
+	Action         AuditActionEnum        `pg:"action"`
+	TargetType     AuditTargetEnum        `pg:"target_type"`
+	TargetID       string                 `pg:"target_id"`
+	Before         map[string]interface{} `pg:"before"`
+	After          map[string]interface{} `pg:"after"`
+	IP             string                 `pg:"ip, use_zero"`
+	RequestID      string                 `pg:"request_id, use_zero"`
+	CreatedAt      time.Time              `pg:"created_at, default:CURRENT_TIMESTAMP"`
+}
+
+type AuditEventFilter struct {
+	ActorID        *int
+	ImpersonatorID *int
+	Action         *AuditActionEnum
+	TargetType     *AuditTargetEnum
+	TargetID       *string
+	From           *time.Time
+	To             *time.Time
+}
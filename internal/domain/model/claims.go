@@ -20,6 +20,15 @@ type Claims struct {
 	ExpiredAt *jwt.NumericDate `json:"expiresAt"`
 	Role      RoleEnum         `json:"role"`
 	Email     string           `json:"email"`
+	Act       *ActorClaims     `json:"act,omitempty"`
+}
+
+// ActorClaims - кто на самом деле действует от имени пользователя (RFC 8693, claim "act").
+// Заполняется только в токенах имперсонации, выданных администратором для поддержки.
+type ActorClaims struct {
+	UserID int      `json:"userId"`
+	Role   RoleEnum `json:"role"`
+	Reason string   `json:"reason"`
 }
 
 func (c Claims) Valid() error {
@@ -30,6 +39,10 @@ func (c Claims) Valid() error {
 	return nil
 }
 
+func (c Claims) IsImpersonated() bool {
+	return c.Act != nil
+}
+
 func NewAuthClaims(userID int, email string, role RoleEnum, daysAuthExpires int) (*Claims, error) {
 	return &Claims{
 		UserID:    userID,
@@ -38,3 +51,17 @@ func NewAuthClaims(userID int, email string, role RoleEnum, daysAuthExpires int)
 		Email:     email,
 	}, nil
 }
+
+func NewImpersonationClaims(target *User, impersonator *Claims, reason string, expiresIn time.Duration) *Claims {
+	return &Claims{
+		UserID:    target.ID,
+		ExpiredAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		Role:      target.Role,
+		Email:     target.Email,
+		Act: &ActorClaims{
+			UserID: impersonator.UserID,
+			Role:   impersonator.Role,
+			Reason: reason,
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// EmailChange - заявка на смену почты. Почта в users меняется только после
+// подтверждения кодом с нового адреса, а старый адрес получает ссылку для отката.
+type EmailChange struct {
+	tableName       struct{}   `pg:"email_changes,alias:ec"`
+	ID              int        `pg:"id,pk"`
+	UserID          int        `pg:"user_id"`
+	OldEmail        string     `pg:"old_email"`
+	NewEmail        string     `pg:"new_email"`
+	Code            string     `pg:"code"`
+	RevertToken     string     `pg:"revert_token"`
+	ExpiredAt       time.Time  `pg:"expired_at"`
+	RevertExpiredAt *time.Time `pg:"revert_expired_at"`
+	ConfirmedAt     *time.Time `pg:"confirmed_at"`
+	RevertedAt      *time.Time `pg:"reverted_at"`
+	CreatedAt       time.Time  `pg:"created_at, default:CURRENT_TIMESTAMP"`
+}
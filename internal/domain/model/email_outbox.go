@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type EmailStatusEnum string
+
+const (
+	EmailStatusPending EmailStatusEnum = "PENDING"
+	EmailStatusSent    EmailStatusEnum = "SENT"
+	// EmailStatusDead — письмо не удалось доставить за отведённое число попыток
+	EmailStatusDead EmailStatusEnum = "DEAD"
+)
+
+// OutboxEmail — письмо в очереди на отправку
+type OutboxEmail struct {
+	tableName struct{}                 `pg:"email_outbox"`
+	ID        int                      `pg:"id,pk"`
+	Recipient string                   `pg:"recipient"`
+	Category  NotificationCategoryEnum `pg:"category"`
+	Subject   string                   `pg:"subject"`
+	TextBody  string                   `pg:"text_body"`
+	HtmlBody  string                   `pg:"html_body"`
+	// ListUnsubscribe — ссылка отписки для заголовка List-Unsubscribe
+	ListUnsubscribe *string         `pg:"list_unsubscribe"`
+	Status          EmailStatusEnum `pg:"status"`
+	Attempts        int             `pg:"attempts,use_zero"`
+	NextAttemptAt   time.Time       `pg:"next_attempt_at"`
+	LastError       *string         `pg:"last_error"`
+	CreatedAt       time.Time       `pg:"created_at"`
+	SentAt          *time.Time      `pg:"sent_at"`
+	// ExpiresAt — после этого времени код или ссылка из письма уже не действуют
+	ExpiresAt *time.Time `pg:"expires_at"`
+	// RedactedAt — когда тело письма стёрто по истечении срока хранения
+	RedactedAt *time.Time `pg:"redacted_at"`
+}
+
+// IsExpired — письмо больше незачем отправлять: код или ссылка из него уже не действуют.
+func (e *OutboxEmail) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && now.After(*e.ExpiresAt)
+}
+
+// MailMessage — готовое к отправке письмо
+type MailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Unsubscribe — ссылка отписки в один клик (RFC 8058), пусто для обязательных писем
+	Unsubscribe string
+}
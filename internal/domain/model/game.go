@@ -6,6 +6,9 @@ type UserGameStats struct {
 	UserId      int
 	GamesAmount int
 	WinsAmount  int
+	DrawsAmount int
+	// ForfeitsAmount — игры, которые пользователь бросил и проиграл из-за этого
+	ForfeitsAmount int
 }
 
 type UserTotalGamesStats struct {
@@ -18,22 +21,31 @@ type Achievements struct {
 	Name        string    `pg:"name"`
 	Description string    `pg:"description"`
 	CreateAt    time.Time `pg:"created_at"`
+	// WinsRequired — после скольких побед ачивка выдаётся автоматически
+	WinsRequired *int `pg:"wins_required"`
 }
 
 type Game struct {
-	ID             int64     `pg:"id, pk"`
-	FirstPlayerID  int64     `pg:"first_player_id"`
-	SecondPlayerID int64     `pg:"second_player_id"`
-	RoomID         string    `pg:"room_id"`
-	WinnerID       int64     `pg:"winner_id"`
-	MetatopicID    int64     `pg:"metatopic_id"`
-	TopicID        int64     `pg:"topic_id"`
-	CreatedAt      time.Time `pg:"created_at"`
+	ID             int64           `pg:"id, pk"`
+	FirstPlayerID  int64           `pg:"first_player_id"`
+	SecondPlayerID int64           `pg:"second_player_id"`
+	RoomID         string          `pg:"room_uid"`
+	WinnerID       int64           `pg:"winner_id"`
+	Outcome        GameOutcomeEnum `pg:"outcome"`
+	MetatopicID    int64           `pg:"metatopic_id"`
+	TopicID        int64           `pg:"topic_id"`
+	CreatedAt      time.Time       `pg:"created_at"`
+}
+
+func (g *Game) HasPlayer(userId int) bool {
+	return userId != 0 && (g.FirstPlayerID == int64(userId) || g.SecondPlayerID == int64(userId))
 }
 
 type StartGame struct {
 	RoomID     string `pg:"id,pk"`
 	FromUserID int    `pg:"from_user_id"` // references
+	// OpponentID — кого создающий комнату игрок вызывает на игру, 0 — никого
+	OpponentID int `pg:"-"`
 }
 
 type FinishGame struct {
@@ -43,9 +55,42 @@ type FinishGame struct {
 }
 
 type GameResult struct {
-	RoomID     string `pg:"id,pk"`
-	WinnerId   int    `pg:"winner_id"` // references
-	ResultText string `pg:"result_text"`
+	RoomID        string `pg:"id,pk"`
+	WinnerId      int    `pg:"winner_id"` // references
+	ResultText    string `pg:"result_text"`
+	Outcome       GameOutcomeEnum
+	AwaitingJudge bool
+}
+
+type GameOutcomeEnum string
+
+const (
+	GameOutcomeWin  GameOutcomeEnum = "WIN"
+	GameOutcomeDraw GameOutcomeEnum = "DRAW"
+	// GameOutcomeForfeit — один из игроков не прислал результат, победа засчитана другому
+	GameOutcomeForfeit GameOutcomeEnum = "FORFEIT"
+	// GameOutcomeAbandoned — результат не прислал ни один игрок
+	GameOutcomeAbandoned GameOutcomeEnum = "ABANDONED"
+	// GameOutcomeNoShow — второй игрок не пришёл, игра не началась
+	GameOutcomeNoShow GameOutcomeEnum = "NO_SHOW"
+)
+
+// HasWinner — исход, при котором у игры есть победитель.
+func (o GameOutcomeEnum) HasWinner() bool {
+	return o == GameOutcomeWin || o == GameOutcomeForfeit
+}
+
+// GameVerdict — итог игры. Outcome пуст, пока итог ждёт судью.
+type GameVerdict struct {
+	WinnerId int
+	Outcome  GameOutcomeEnum
+	// AwaitingJudge — итог вынесет судья через judgeGame, а не ResultPolicy
+	AwaitingJudge bool
+}
+
+// Decided — итог известен, и победитель указан ровно тогда, когда он положен исходу.
+func (v GameVerdict) Decided() bool {
+	return v.Outcome != "" && v.Outcome.HasWinner() == (v.WinnerId != 0)
 }
 
 type GameStatusEnum string
@@ -58,18 +103,45 @@ const (
 )
 
 type GameStatus struct {
+	tableName struct{} `pg:"live_games,alias:lg"`
+
 	ID             string `pg:"id,pk"`
 	FirstPlayerId  int    `pg:"first_player_id"`  // references
 	SecondPlayerId int    `pg:"second_player_id"` // references
 
-	FirstPlayerScore  int
-	SecondPlayerScore int
+	FirstPlayerScore  int `pg:"first_player_score,use_zero"`
+	SecondPlayerScore int `pg:"second_player_score,use_zero"`
+	// Прислал ли игрок результат: нулевой результат тоже результат
+	FirstPlayerReported  bool `pg:"first_player_reported,use_zero"`
+	SecondPlayerReported bool `pg:"second_player_reported,use_zero"`
 
-	FirstRequest       time.Time
-	FirstFinishRequest *time.Time
+	FirstRequest       time.Time  `pg:"first_request"`
+	FirstFinishRequest *time.Time `pg:"first_finish_request"`
+
+	GameStatusEnum GameStatusEnum  `pg:"status"`
+	WinnerId       int             `pg:"winner_id"` // references
+	Outcome        GameOutcomeEnum `pg:"outcome"`
+	AwaitingJudge  bool            `pg:"awaiting_judge,use_zero"`
+	StartAt        time.Time       `pg:"start_at"`
+	FinishAt       time.Time       `pg:"finish_at"`
+
+	// Время переходов жизненного цикла, заполняет Transition
+	CreatedAt  time.Time  `pg:"created_at"`
+	StartedAt  *time.Time `pg:"started_at"`
+	DeclinedAt *time.Time `pg:"declined_at"`
+	FinishedAt *time.Time `pg:"finished_at"`
+}
+
+func (g *GameStatus) Verdict() GameVerdict {
+	return GameVerdict{
+		WinnerId:      g.WinnerId,
+		Outcome:       g.Outcome,
+		AwaitingJudge: g.AwaitingJudge,
+	}
+}
 
-	GameStatusEnum GameStatusEnum `pg:"status"`
-	WinnerId       int            `pg:"winner_id"` // references
-	StartAt        time.Time      `pg:"start_at"`
-	FinishAt       time.Time      `pg:"finish_at"`
+func (g *GameStatus) ApplyVerdict(verdict GameVerdict) {
+	g.WinnerId = verdict.WinnerId
+	g.Outcome = verdict.Outcome
+	g.AwaitingJudge = verdict.AwaitingJudge
 }
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	ErrGameFull      = tracerr.New("game already has two players")
+	ErrNotGamePlayer = tracerr.New("user is not a player of the game")
+	// ErrNotAwaitingJudge — судья выносит итог только завершённой игре, которую не решила ResultPolicy
+	ErrNotAwaitingJudge = tracerr.New("game result is not awaiting a judge")
+)
+
+// gameTransitions — допустимые переходы жизненного цикла игры:
+// PENDING → STARTED → FINISHED, либо PENDING → DECLINED, если второй игрок не пришёл вовремя.
+// DECLINED и FINISHED — конечные статусы.
+var gameTransitions = map[GameStatusEnum][]GameStatusEnum{
+	GameStatusPending: {GameStatusStarted, GameStatusDeclined},
+	GameStatusStarted: {GameStatusFinished},
+}
+
+// GameTransitionError — попытка перевести игру в статус, недостижимый из текущего.
+type GameTransitionError struct {
+	From GameStatusEnum
+	To   GameStatusEnum
+}
+
+func (e *GameTransitionError) Error() string {
+	return fmt.Sprintf("illegal game transition from %s to %s", e.From, e.To)
+}
+
+func (s GameStatusEnum) CanTransitionTo(next GameStatusEnum) bool {
+	for _, allowed := range gameTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s GameStatusEnum) IsTerminal() bool {
+	return len(gameTransitions[s]) == 0
+}
+
+// NewPendingGame — игра, в которую пришёл первый игрок.
+func NewPendingGame(roomId string, firstPlayerId int, at time.Time) GameStatus {
+	return GameStatus{
+		ID:             roomId,
+		FirstPlayerId:  firstPlayerId,
+		FirstRequest:   at,
+		GameStatusEnum: GameStatusPending,
+		StartAt:        at,
+		FinishAt:       at,
+		CreatedAt:      at,
+	}
+}
+
+// Transition переводит игру в статус next и запоминает время перехода.
+// Недопустимый переход возвращает *GameTransitionError и не меняет игру.
+func (g *GameStatus) Transition(next GameStatusEnum, at time.Time) error {
+	if !g.GameStatusEnum.CanTransitionTo(next) {
+		return &GameTransitionError{From: g.GameStatusEnum, To: next}
+	}
+
+	g.GameStatusEnum = next
+	switch next {
+	case GameStatusStarted:
+		g.StartedAt = &at
+	case GameStatusDeclined:
+		g.DeclinedAt = &at
+	case GameStatusFinished:
+		g.FinishedAt = &at
+	}
+
+	return nil
+}
+
+func (g *GameStatus) HasPlayer(userId int) bool {
+	return userId != 0 && (g.FirstPlayerId == userId || g.SecondPlayerId == userId)
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGameStatusTransition(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		from  GameStatusEnum
+		to    GameStatusEnum
+		legal bool
+	}{
+		{from: GameStatusPending, to: GameStatusStarted, legal: true},
+		{from: GameStatusPending, to: GameStatusDeclined, legal: true},
+		{from: GameStatusStarted, to: GameStatusFinished, legal: true},
+
+		{from: GameStatusPending, to: GameStatusPending},
+		{from: GameStatusPending, to: GameStatusFinished},
+		{from: GameStatusStarted, to: GameStatusPending},
+		{from: GameStatusStarted, to: GameStatusStarted},
+		{from: GameStatusStarted, to: GameStatusDeclined},
+		{from: GameStatusFinished, to: GameStatusPending},
+		{from: GameStatusFinished, to: GameStatusStarted},
+		{from: GameStatusFinished, to: GameStatusFinished},
+		{from: GameStatusDeclined, to: GameStatusStarted},
+		{from: GameStatusDeclined, to: GameStatusFinished},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			game := GameStatus{ID: "room", GameStatusEnum: tt.from}
+			err := game.Transition(tt.to, at)
+
+			if !tt.legal {
+				var transitionErr *GameTransitionError
+				if !errors.As(err, &transitionErr) {
+					t.Fatalf("Transition err = %v, want *GameTransitionError", err)
+				}
+				if transitionErr.From != tt.from || transitionErr.To != tt.to {
+					t.Fatalf("GameTransitionError = %s -> %s, want %s -> %s",
+						transitionErr.From, transitionErr.To, tt.from, tt.to)
+				}
+				if game.GameStatusEnum != tt.from || game.StartedAt != nil || game.DeclinedAt != nil || game.FinishedAt != nil {
+					t.Fatalf("illegal transition changed the game: %+v", game)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Transition: %v", err)
+			}
+			if game.GameStatusEnum != tt.to {
+				t.Fatalf("status = %s, want %s", game.GameStatusEnum, tt.to)
+			}
+
+			stamps := map[GameStatusEnum]*time.Time{
+				GameStatusStarted:  game.StartedAt,
+				GameStatusDeclined: game.DeclinedAt,
+				GameStatusFinished: game.FinishedAt,
+			}
+			if stamp := stamps[tt.to]; stamp == nil || !stamp.Equal(at) {
+				t.Fatalf("transition time = %v, want %v", stamp, at)
+			}
+		})
+	}
+}
+
+func TestGameStatusIsTerminal(t *testing.T) {
+	tests := []struct {
+		status   GameStatusEnum
+		terminal bool
+	}{
+		{status: GameStatusPending},
+		{status: GameStatusStarted},
+		{status: GameStatusDeclined, terminal: true},
+		{status: GameStatusFinished, terminal: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.IsTerminal(); got != tt.terminal {
+			t.Fatalf("%s.IsTerminal() = %v, want %v", tt.status, got, tt.terminal)
+		}
+	}
+}
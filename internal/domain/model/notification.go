@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type NotificationCategoryEnum string
+
+const (
+	// NotificationCategorySecurity — коды и уведомления о безопасности аккаунта, от них нельзя отписаться
+	NotificationCategorySecurity     NotificationCategoryEnum = "SECURITY"
+	NotificationCategoryGameInvites  NotificationCategoryEnum = "GAME_INVITES"
+	NotificationCategoryAchievements NotificationCategoryEnum = "ACHIEVEMENTS"
+	// NotificationCategoryDigest — итоги игр и решения модераторов по предложенным темам
+	NotificationCategoryDigest NotificationCategoryEnum = "DIGEST"
+)
+
+var NotificationCategories = []NotificationCategoryEnum{
+	NotificationCategorySecurity,
+	NotificationCategoryGameInvites,
+	NotificationCategoryAchievements,
+	NotificationCategoryDigest,
+}
+
+// Optional сообщает, можно ли отписаться от категории
+func (c NotificationCategoryEnum) Optional() bool {
+	return c != NotificationCategorySecurity
+}
+
+func (c NotificationCategoryEnum) Valid() bool {
+	for _, category := range NotificationCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+type NotificationPreference struct {
+	tableName struct{}                 `pg:"notification_preferences"`
+	UserID    int                      `pg:"user_id,pk"`
+	Category  NotificationCategoryEnum `pg:"category,pk"`
+	Enabled   bool                     `pg:"enabled,use_zero"`
+	UpdatedAt time.Time                `pg:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type NotificationTypeEnum string
+
+const (
+	NotificationTypeTopicApproved       NotificationTypeEnum = "TOPIC_APPROVED"
+	NotificationTypeTopicDeclined       NotificationTypeEnum = "TOPIC_DECLINED"
+	NotificationTypeGameFinished        NotificationTypeEnum = "GAME_FINISHED"
+	NotificationTypeAchievementUnlocked NotificationTypeEnum = "ACHIEVEMENT_UNLOCKED"
+	NotificationTypeGameChallenge       NotificationTypeEnum = "GAME_CHALLENGE"
+)
+
+// Notification — уведомление в приложении
+type Notification struct {
+	tableName struct{}               `pg:"notifications"`
+	ID        int                    `pg:"id,pk"`
+	UserID    int                    `pg:"user_id"`
+	Type      NotificationTypeEnum   `pg:"type"`
+	Payload   map[string]interface{} `pg:"payload"`
+	DedupKey  string                 `pg:"dedup_key"`
+	ReadAt    *time.Time             `pg:"read_at"`
+	CreatedAt time.Time              `pg:"created_at"`
+}
@@ -0,0 +1,10 @@
+package model
+
+type PasswordViolationEnum string
+
+const (
+	PasswordViolationTooShort         PasswordViolationEnum = "TOO_SHORT"
+	PasswordViolationContainsUsername PasswordViolationEnum = "CONTAINS_USERNAME"
+	PasswordViolationContainsEmail    PasswordViolationEnum = "CONTAINS_EMAIL"
+	PasswordViolationBreached         PasswordViolationEnum = "BREACHED"
+)
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+type ReportReasonEnum string
+
+const (
+	ReportReasonCheating          ReportReasonEnum = "CHEATING"
+	ReportReasonHarassment        ReportReasonEnum = "HARASSMENT"
+	ReportReasonHateSpeech        ReportReasonEnum = "HATE_SPEECH"
+	ReportReasonSpam              ReportReasonEnum = "SPAM"
+	ReportReasonInappropriateName ReportReasonEnum = "INAPPROPRIATE_NAME"
+	ReportReasonOther             ReportReasonEnum = "OTHER"
+)
+
+type ReportStatusEnum string
+
+const (
+	ReportStatusOpen     ReportStatusEnum = "OPEN"
+	ReportStatusResolved ReportStatusEnum = "RESOLVED"
+)
+
+type ModerationActionEnum string
+
+const (
+	ModerationActionWarn    ModerationActionEnum = "WARN"
+	ModerationActionBan     ModerationActionEnum = "BAN"
+	ModerationActionDismiss ModerationActionEnum = "DISMISS"
+)
+
+type Report struct {
+	tableName      struct{}              `pg:"reports,alias:r"`
+	ID             int                   `pg:"id,pk"`
+	ReporterID     int                   `pg:"reporter_id"`
+	ReportedUserID int                   `pg:"reported_user_id"`
+	GameRoomID     string                `pg:"game_room_id"`
+	Reason         ReportReasonEnum      `pg:"reason, type:report_reason_enum"`
+	Comment        string                `pg:"comment, use_zero"`
+	Status         ReportStatusEnum      `pg:"status, type:report_status_enum"`
+	Resolution     *ModerationActionEnum `pg:"resolution, type:moderation_action_enum"`
+	CreatedAt      time.Time             `pg:"created_at, default:CURRENT_TIMESTAMP"`
+	ResolvedAt     *time.Time            `pg:"resolved_at"`
+}
+
+// ModerationAction - запись о действии модератора над пользователем.
+// Хранится отдельно от жалобы, чтобы история нарушителя не терялась после её закрытия.
+type ModerationAction struct {
+	tableName   struct{}             `pg:"moderation_actions,alias:ma"`
+	ID          int                  `pg:"id,pk"`
+	ReportID    *int                 `pg:"report_id"`
+	UserID      int                  `pg:"user_id"`
+	ModeratorID int                  `pg:"moderator_id"`
+	Action      ModerationActionEnum `pg:"action, type:moderation_action_enum"`
+	Comment     string               `pg:"comment, use_zero"`
+	CreatedAt   time.Time            `pg:"created_at, default:CURRENT_TIMESTAMP"`
+}
+
+// OffenderStats - сводка по нарушителю, чтобы модератор видел повторные нарушения.
+type OffenderStats struct {
+	UserID        int
+	ReportsCount  int
+	WarningsCount int
+	BansCount     int
+}
+
+type ReportDetails struct {
+	Report        Report
+	Actions       []ModerationAction
+	OffenderStats OffenderStats
+}
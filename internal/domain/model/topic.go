@@ -24,6 +24,8 @@ type Topic struct {
 	Name      string              `pg:"name"`
 	Status    ApprovingStatusEnum `pg:"status, type:approving_status_enum"`
 	CreatedAt time.Time           `pg:"created_at, default:CURRENT_TIMESTAMP"`
+	// SuggestedBy — пользователь, предложивший тему
+	SuggestedBy *int `pg:"suggested_by"`
 }
 
 type MetatopicsTopics struct {
@@ -15,38 +15,86 @@ const (
 	RoleDefaultUser    RoleEnum = "USER"
 )
 
+// LocaleEnum — язык, на котором пользователь получает письма
+type LocaleEnum string
+
+const (
+	LocaleRu LocaleEnum = "ru"
+	LocaleEn LocaleEnum = "en"
+)
+
 type Image struct {
 	tableName   struct{}  `pg:"images"`
 	ID          int       `pg:"id,pk"`
 	Hash        []byte    `pg:"hash"`
 	ContentType string    `pg:"content_type"`
 	File        []byte    `pg:"file"`
+	StorageKey  string    `pg:"storage_key"`        // Ключ в хранилище блобов, если File пуст
+	RefCount    int       `pg:"ref_count,use_zero"` // Количество пользователей, ссылающихся на изображение
 	CreatedAt   time.Time `pg:"created_at"`
 	UpdatedAt   time.Time `pg:"updated_at"`
 }
 
+// ImageVariant — обработанная копия изображения заданного размера и формата
+type ImageVariant struct {
+	tableName   struct{}  `pg:"image_variants"`
+	ImageID     int       `pg:"image_id,pk"`
+	Size        int       `pg:"size,pk"`
+	ContentType string    `pg:"content_type,pk"`
+	File        []byte    `pg:"file"`
+	StorageKey  string    `pg:"storage_key"`
+	CreatedAt   time.Time `pg:"created_at"`
+}
+
+// ImageFile — файл изображения или его варианта, который ещё хранится прямо в таблице
+type ImageFile struct {
+	ImageID     int
+	Variant     bool
+	Size        int
+	ContentType string
+	Hash        []byte
+	File        []byte
+}
+
+// ImageContent — файл изображения, готовый к отдаче клиенту
+type ImageContent struct {
+	Hash        []byte
+	Size        int
+	ContentType string
+	File        []byte
+	StorageKey  string
+	CreatedAt   time.Time
+}
+
 type User struct {
-	tableName struct{}  `pg:"users"`
-	ID        int       `pg:"id,pk"`
-	Role      RoleEnum  `pg:"role"`
-	Username  string    `pg:"username"`
-	Email     string    `pg:"email"`
-	Password  string    `pg:"password"`
-	CreatedAt time.Time `pg:"created_at"`
-	UpdatedAt time.Time `pg:"updated_at"`
-	ImageId   int       `pg:"image_id"`
-	Image     *Image    `pg:"fk:image_id,rel:has-one"`
+	tableName struct{}   `pg:"users"`
+	ID        int        `pg:"id,pk"`
+	Role      RoleEnum   `pg:"role"`
+	Username  string     `pg:"username"`
+	Email     string     `pg:"email"`
+	Password  string     `pg:"password"`
+	CreatedAt time.Time  `pg:"created_at"`
+	UpdatedAt time.Time  `pg:"updated_at"`
+	ImageId   int        `pg:"image_id"`
+	Image     *Image     `pg:"fk:image_id,rel:has-one"`
+	BannedAt  *time.Time `pg:"banned_at"`
+	Locale    LocaleEnum `pg:"locale"`
+}
+
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
 }
 
 // Валидация полей структуры User
 func (u *User) Validate() error {
 	return validation.ValidateStruct(u,
-		validation.Field(&u.Email, validation.Required, is.Email), // Проверка, что Email валиден
-		validation.Field(&u.Role, validation.Required),            // Поле обязательно
-		validation.Field(&u.Username, validation.Required),        // Поле обязательно
-		validation.Field(&u.Password, validation.Required),        // Поле обязательно
-		validation.Field(&u.CreatedAt, validation.Required),       // Поле обязательно
-		validation.Field(&u.UpdatedAt, validation.Required),       // Поле обязательно
+		validation.Field(&u.Email, validation.Required, is.Email),      // Проверка, что Email валиден
+		validation.Field(&u.Role, validation.Required),                 // Поле обязательно
+		validation.Field(&u.Username, validation.Required),             // Поле обязательно
+		validation.Field(&u.Password, validation.Required),             // Поле обязательно
+		validation.Field(&u.CreatedAt, validation.Required),            // Поле обязательно
+		validation.Field(&u.UpdatedAt, validation.Required),            // Поле обязательно
+		validation.Field(&u.Locale, validation.In(LocaleRu, LocaleEn)), // Только поддерживаемые языки
 
 	)
 }
@@ -54,7 +102,7 @@ func (u *User) Validate() error {
 // Валидация полей структуры Image
 func (i *Image) Validate() error {
 	return validation.ValidateStruct(i,
-		validation.Field(&i.ContentType, validation.Required), // Поле обязательно
-		validation.Field(&i.File, validation.Required),        // Поле обязательно
+		validation.Field(&i.ContentType, validation.Required),                   // Поле обязательно
+		validation.Field(&i.File, validation.Required.When(i.StorageKey == "")), // Файл или ключ в хранилище
 	)
 }
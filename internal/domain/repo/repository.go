@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 )
@@ -9,15 +10,49 @@ import (
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *model.User) (*model.User, error)
 	UpdateUser(ctx context.Context, user *model.User) (*model.User, error)
+	// UpdateUserPassword меняет только хеш пароля, не трогая остальные поля пользователя.
+	UpdateUserPassword(ctx context.Context, userId int, password string) error
 	FindUserByEmail(ctx context.Context, email string) (*model.User, error)
 	GetUsers(ctx context.Context, limit int, offset int) ([]*model.User, error)
 	FindUserByID(ctx context.Context, ID int) (*model.User, error)
-	UploadImage(ctx context.Context, userId int, image, hash []byte, contentType string) error
-	DownloadImage(ctx context.Context, userId int) ([]byte, string, error)
+	AttachImageByHash(ctx context.Context, userId int, hash []byte) (bool, error)
+	// SetUserImage привязывает к пользователю изображение, которое он уже загружал или ставил себе,
+	// imageId = 0 убирает аватар. Любое другое изображение — ErrNotFound.
+	SetUserImage(ctx context.Context, userId int, imageId int) error
+	UploadImage(ctx context.Context, userId int, image *model.Image, variants []*model.ImageVariant) (bool, error)
+	DownloadImage(ctx context.Context, userId int, size int, contentTypes []string) (*model.ImageContent, error)
+	// DeleteUnreferencedImages удаляет изображения и ставит их файлы в очередь на удаление из хранилища блобов.
+	DeleteUnreferencedImages(ctx context.Context, before time.Time) (int, error)
+	// GetBlobDeletions возвращает ключи файлов из очереди на удаление, сначала те, что пробовали реже.
+	GetBlobDeletions(ctx context.Context, limit int) ([]string, error)
+	CompleteBlobDeletion(ctx context.Context, storageKey string) error
+	// FailBlobDeletion оставляет файл в очереди до следующей попытки и запоминает ошибку.
+	FailBlobDeletion(ctx context.Context, storageKey string, lastError string) error
+	GetInlineImageFiles(ctx context.Context, limit int) ([]*model.ImageFile, error)
+	MoveImageFileToStorage(ctx context.Context, file *model.ImageFile, storageKey string) error
+}
+
+// BlobStore хранит содержимое файлов по ключу. Отсутствующий ключ — ErrNotFound.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Mailer доставляет письма: по SMTP или, при разработке, в файлы, лог или память.
+type Mailer interface {
+	Send(ctx context.Context, message *model.MailMessage) error
+}
+
+// EventBus доставляет события подписчикам темы, пока жив контекст подписки.
+type EventBus[T any] interface {
+	Publish(topic string, event T)
+	Subscribe(ctx context.Context, topic string) <-chan T
 }
 
 type RecoveryCodeRepository interface {
-	CreateRecoveryCode(ctx context.Context, code *model.RecoveryCode) (*model.RecoveryCode, error)
+	// CreateRecoveryCode сохраняет код и в той же транзакции ставит в очередь письмо с ним.
+	CreateRecoveryCode(ctx context.Context, code *model.RecoveryCode, email *model.OutboxEmail) (*model.RecoveryCode, error)
 	FindRecoveryCodeByEmailAndCode(ctx context.Context, email string, code string) (*model.RecoveryCode, error)
 	ExistsRecoveryCodeByEmailAndCode(ctx context.Context, email string, code string) (bool, error)
 }
@@ -28,6 +63,19 @@ type GameStatsRepository interface {
 
 type AchievmentsRepository interface {
 	GetAchievmentsByUserId(ctx context.Context, userId int, limit int, offset int) ([]*model.Achievements, error)
+	// AwardWinAchievements выдаёт ачивки, для которых у пользователя уже достаточно побед,
+	// и возвращает только выданные сейчас.
+	AwardWinAchievements(ctx context.Context, userId int) ([]*model.Achievements, error)
+}
+
+type NotificationRepository interface {
+	// CreateNotification не создаёт дубль, если у пользователя уже есть уведомление с тем же DedupKey.
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+	// GetNotifications возвращает уведомления от новых к старым, начиная со строго старше afterId.
+	GetNotifications(ctx context.Context, userId int, afterId *int, limit int, unreadOnly bool) ([]model.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userId int) (int, error)
+	// MarkNotificationsRead отмечает прочитанными уведомления ids, а при пустом ids — все.
+	MarkNotificationsRead(ctx context.Context, userId int, ids []int) error
 }
 
 type TopicRepository interface {
@@ -41,8 +89,70 @@ type TopicRepository interface {
 type GameRepository interface {
 	StartGame(ctx context.Context, startGame model.StartGame) (model.GameStatus, error)
 	GetGameById(ctx context.Context, id string) (model.GameStatus, error)
-	FinishGameByDeadline(ctx context.Context, fromUserId int, currentGameStatus model.GameStatus) (model.GameStatus, error)
-	SetWinnerId(ctx context.Context, roomID string, winnerID int) error
+	// GetPlayedGame возвращает сохранённую в games игру комнаты. В отличие от GetGameById, она
+	// доступна и после того, как идущая игра забыта.
+	GetPlayedGame(ctx context.Context, roomID string) (model.Game, error)
+	FinishGameByDeadline(ctx context.Context, roomId string) (model.GameStatus, error)
 	IsGameOverByDeadline(ctx context.Context, gameId string) bool
 	FinishGame(ctx context.Context, finishGame model.FinishGame) (model.GameResult, error)
+	ExpireGames(ctx context.Context, now time.Time) ([]model.GameStatus, error)
+	EvictGames(ctx context.Context, before time.Time) (int, error)
+	// JudgeGame записывает итог, вынесенный судьёй игре, которая его ждёт.
+	JudgeGame(ctx context.Context, roomId string, verdict model.GameVerdict) (model.GameStatus, error)
+}
+
+// ResultPolicy решает итог игры, в которой результат прислали оба игрока: по этим результатам
+// или передавая решение судье. Неявку и брошенные игры решает сам GameRepository.
+type ResultPolicy interface {
+	Decide(game model.GameStatus) model.GameVerdict
+}
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report *model.Report) (*model.Report, error)
+	GetReport(ctx context.Context, reportId int) (*model.ReportDetails, error)
+	GetReports(ctx context.Context, statuses []model.ReportStatusEnum, reportedUserId *int, pageSize, pageNumber int) ([]model.ReportDetails, int, error)
+	ResolveReport(ctx context.Context, reportId int, action *model.ModerationAction) (*model.ReportDetails, error)
+	GetModerationActions(ctx context.Context, userId int, limit, offset int) ([]model.ModerationAction, error)
+	GetOffenderStats(ctx context.Context, userId int) (*model.OffenderStats, error)
+}
+
+type AuditRepository interface {
+	CreateEvent(ctx context.Context, event *model.AuditEvent) error
+	// GetEvents возвращает события от новых к старым, начиная с события строго старше afterId.
+	GetEvents(ctx context.Context, filter model.AuditEventFilter, afterId *int, limit int) ([]model.AuditEvent, error)
+}
+
+type EmailChangeRepository interface {
+	// CreateEmailChange заменяет предыдущую неподтверждённую заявку пользователя, если она была,
+	// и ставит в очередь письмо с кодом.
+	CreateEmailChange(ctx context.Context, change *model.EmailChange, email *model.OutboxEmail) (*model.EmailChange, error)
+	FindPendingEmailChange(ctx context.Context, userId int, code string) (*model.EmailChange, error)
+	FindEmailChangeByRevertToken(ctx context.Context, token string) (*model.EmailChange, error)
+	// ConfirmEmailChange переключает users.email на новый адрес, сбрасывает коды восстановления
+	// и ставит в очередь уведомление на старый адрес.
+	ConfirmEmailChange(ctx context.Context, change *model.EmailChange, email *model.OutboxEmail) error
+	// RevertEmailChange возвращает users.email на старый адрес и сбрасывает коды восстановления.
+	RevertEmailChange(ctx context.Context, change *model.EmailChange) error
+}
+
+type NotificationPreferenceRepository interface {
+	// GetNotificationPreferences возвращает только явно заданные настройки пользователя.
+	GetNotificationPreferences(ctx context.Context, userId int) ([]model.NotificationPreference, error)
+	SetNotificationPreferences(ctx context.Context, preferences []model.NotificationPreference) error
+}
+
+type EmailOutboxRepository interface {
+	// EnqueueEmail ставит в очередь письмо, не связанное с другими изменениями.
+	EnqueueEmail(ctx context.Context, email *model.OutboxEmail) error
+	// ClaimDueEmails выбирает письма, которые пора отправить, и откладывает их на lease,
+	// чтобы другой воркер не взял их повторно.
+	ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]*model.OutboxEmail, error)
+	MarkEmailSent(ctx context.Context, id int) error
+	// MarkEmailFailed сохраняет число попыток, ошибку, статус и время следующей попытки.
+	MarkEmailFailed(ctx context.Context, email *model.OutboxEmail) error
+	GetEmails(ctx context.Context, statuses []model.EmailStatusEnum, limit int, offset int) ([]*model.OutboxEmail, error)
+	// RetryEmail возвращает недоставленное письмо в очередь. Просроченное или стёртое письмо — ErrNotFound.
+	RetryEmail(ctx context.Context, id int) (*model.OutboxEmail, error)
+	// RedactEmails стирает тела отправленных и недоставленных писем, созданных раньше before.
+	RedactEmails(ctx context.Context, before time.Time) (int, error)
 }
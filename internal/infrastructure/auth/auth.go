@@ -13,10 +13,11 @@ const (
 )
 
 type Config struct {
-	JwtSecretAuth       string
-	JwtSecretMessages   string
-	DaysAuthExpires     int
-	DaysRecoveryExpires int
+	JwtSecretAuth               string
+	JwtSecretMessages           string
+	DaysAuthExpires             int
+	DaysRecoveryExpires         int
+	MinutesImpersonationExpires int
 }
 
 type AuthService struct {
@@ -48,6 +49,23 @@ func (a *AuthService) GenerateTokenByClaims(claims *model.Claims) (string, error
 	return ss, nil
 }
 
+// GenerateImpersonationToken подписывает claims как есть, не продлевая срок жизни,
+// чтобы токен имперсонации оставался короткоживущим.
+func (a *AuthService) GenerateImpersonationToken(claims *model.Claims) (string, error) {
+	if !claims.IsImpersonated() {
+		return "", tracerr.New("claims without impersonator")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	ss, err := token.SignedString([]byte(a.cfg.JwtSecretAuth))
+	if err != nil {
+		return "", tracerr.Wrap(err)
+	}
+
+	return ss, nil
+}
+
 func (a *AuthService) ParseToken(jwtStr string) (*model.Claims, error) {
 	var claims model.Claims
 	_, err := jwt.ParseWithClaims(jwtStr, &claims, func(token *jwt.Token) (interface{}, error) {
@@ -71,3 +89,7 @@ func (a *AuthService) GetDaysAuthExpires() int {
 func (a *AuthService) GetDaysRecoveryExpires() int {
 	return a.cfg.DaysRecoveryExpires
 }
+
+func (a *AuthService) GetImpersonationExpires() time.Duration {
+	return time.Duration(a.cfg.MinutesImpersonationExpires) * time.Minute
+}
@@ -0,0 +1,100 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.BlobStore = (*FSStore)(nil)
+)
+
+// FSStore хранит блобы файлами в локальной директории, ключ — относительный путь.
+type FSStore struct {
+	root string
+}
+
+func NewFSStore(root string) (*FSStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, tracerr.Errorf("failed create blob directory: %w", err)
+	}
+
+	return &FSStore{root: root}, nil
+}
+
+func (s *FSStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return tracerr.Errorf("failed put blob: %w", err)
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы читатели не увидели недописанный блоб
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return tracerr.Errorf("failed put blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return tracerr.Errorf("failed put blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return tracerr.Errorf("failed put blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return tracerr.Errorf("failed put blob: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FSStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Errorf("failed get blob: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s *FSStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return tracerr.Errorf("failed delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// path не даёт ключу выйти за пределы корневой директории.
+func (s *FSStore) path(key string) (string, error) {
+	local := filepath.FromSlash(key)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("%w: invalid blob key %q", repo.ErrValidation, key)
+	}
+
+	return filepath.Join(s.root, local), nil
+}
@@ -0,0 +1,233 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+)
+
+const (
+	testRegion    = "us-east-1"
+	testBucket    = "avatars"
+	testAccessKey = "minio"
+	testSecretKey = "minio-secret"
+)
+
+// fakeS3 — бакет в памяти, который, как MinIO, проверяет подпись AWS Signature V4 каждого запроса.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(t *testing.T) *httptest.Server {
+	fake := &fakeS3{t: t, objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := verifySignature(r, body); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + testBucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature заново вычисляет подпись по запросу, каким его получил сервер.
+func verifySignature(r *http.Request, body []byte) error {
+	amzDate := r.Header.Get("X-Amz-Date")
+	if _, err := time.Parse("20060102T150405Z", amzDate); err != nil {
+		return errors.New("missing or malformed X-Amz-Date")
+	}
+
+	payloadHash := hex.EncodeToString(sha256Sum(body))
+	if r.Header.Get("X-Amz-Content-Sha256") != payloadHash {
+		return errors.New("X-Amz-Content-Sha256 does not match the body")
+	}
+
+	date := amzDate[:8]
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	canonicalRequest := r.Method + "\n" +
+		r.URL.EscapedPath() + "\n" +
+		r.URL.RawQuery + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		payloadHash
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" +
+		hex.EncodeToString(sha256Sum([]byte(canonicalRequest)))
+
+	key := hmacSum([]byte("AWS4"+testSecretKey), date)
+	for _, part := range []string{testRegion, "s3", "aws4_request"} {
+		key = hmacSum(key, part)
+	}
+
+	want := "AWS4-HMAC-SHA256 Credential=" + testAccessKey + "/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date" +
+		", Signature=" + hex.EncodeToString(hmacSum(key, stringToSign))
+	if got := r.Header.Get("Authorization"); got != want {
+		return errors.New("signature mismatch: " + got)
+	}
+
+	return nil
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func hmacSum(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func newTestS3Store(t *testing.T, endpoint string, secretKey string) *S3Store {
+	store, err := NewS3Store(S3Config{
+		Endpoint:  endpoint,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: secretKey,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+	return store
+}
+
+func TestS3StorePutGetDelete(t *testing.T) {
+	server := newFakeS3(t)
+	store := newTestS3Store(t, server.URL, testSecretKey)
+	ctx := context.Background()
+
+	key := "images/ab12/nonce/128.webp"
+	data := []byte("avatar bytes")
+
+	if err := store.Put(ctx, key, data, "image/webp"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Fatalf("Get = %q, want %q", got, data)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, repo.ErrNotFound) {
+		t.Fatalf("Get after Delete: err = %v, want repo.ErrNotFound", err)
+	}
+
+	// Удаление отсутствующего ключа не ошибка: сборщик изображений может повторить его
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("repeated Delete: %v", err)
+	}
+}
+
+func TestS3StoreEmptyBodySignature(t *testing.T) {
+	server := newFakeS3(t)
+	store := newTestS3Store(t, server.URL, testSecretKey)
+
+	if err := store.Put(context.Background(), "empty", nil, "application/octet-stream"); err != nil {
+		t.Fatalf("Put empty: %v", err)
+	}
+}
+
+func TestS3StoreEndpointWithPathPrefix(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	store := newTestS3Store(t, server.URL+"/storage/", testSecretKey)
+	if err := store.Put(context.Background(), "/a/b.png", []byte("x"), "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if want := "/storage/" + testBucket + "/a/b.png"; gotPath != want {
+		t.Fatalf("path = %q, want %q", gotPath, want)
+	}
+}
+
+func TestS3StoreRejectedSignature(t *testing.T) {
+	var rejected bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if verifySignature(r, body) != nil {
+			rejected = true
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("SignatureDoesNotMatch"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	store := newTestS3Store(t, server.URL, "wrong-secret")
+	err := store.Put(context.Background(), "key", []byte("data"), "text/plain")
+	if err == nil || !rejected {
+		t.Fatalf("Put with wrong secret: err = %v, rejected = %v", err, rejected)
+	}
+	if !strings.Contains(err.Error(), "403") {
+		t.Fatalf("error %q does not mention the status", err)
+	}
+}
@@ -0,0 +1,47 @@
+package gameresult
+
+import (
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+)
+
+var (
+	_ repo.ResultPolicy = (*ScorePolicy)(nil)
+	_ repo.ResultPolicy = (*JudgedPolicy)(nil)
+)
+
+// ScorePolicy решает игры, в которых результат прислали оба игрока: побеждает тот, кто продержался
+// в игре дольше соперника больше чем на DrawThreshold. Меньшая разница — ничья.
+type ScorePolicy struct {
+	DrawThreshold time.Duration
+}
+
+func NewScorePolicy(drawThreshold time.Duration) *ScorePolicy {
+	return &ScorePolicy{DrawThreshold: drawThreshold}
+}
+
+func (p *ScorePolicy) Decide(game model.GameStatus) model.GameVerdict {
+	delta := time.Duration(game.FirstPlayerScore-game.SecondPlayerScore) * time.Second
+	switch {
+	case delta > p.DrawThreshold:
+		return model.GameVerdict{WinnerId: game.FirstPlayerId, Outcome: model.GameOutcomeWin}
+	case delta < -p.DrawThreshold:
+		return model.GameVerdict{WinnerId: game.SecondPlayerId, Outcome: model.GameOutcomeWin}
+	default:
+		return model.GameVerdict{Outcome: model.GameOutcomeDraw}
+	}
+}
+
+// JudgedPolicy оставляет судье игры, в которых результат прислали оба игрока: игра завершается
+// без итога, пока его не вынесут.
+type JudgedPolicy struct{}
+
+func NewJudgedPolicy() *JudgedPolicy {
+	return &JudgedPolicy{}
+}
+
+func (p *JudgedPolicy) Decide(game model.GameStatus) model.GameVerdict {
+	return model.GameVerdict{AwaitingJudge: true}
+}
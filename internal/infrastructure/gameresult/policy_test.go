@@ -0,0 +1,78 @@
+package gameresult
+
+import (
+	"testing"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+)
+
+const (
+	firstPlayer  = 1
+	secondPlayer = 2
+)
+
+func TestScorePolicyDecide(t *testing.T) {
+	policy := NewScorePolicy(10 * time.Second)
+
+	win := func(winnerId int) model.GameVerdict {
+		return model.GameVerdict{WinnerId: winnerId, Outcome: model.GameOutcomeWin}
+	}
+	draw := model.GameVerdict{Outcome: model.GameOutcomeDraw}
+
+	tests := []struct {
+		name        string
+		firstScore  int
+		secondScore int
+		want        model.GameVerdict
+	}{
+		{name: "equal scores", firstScore: 120, secondScore: 120, want: draw},
+		{name: "first ahead below threshold", firstScore: 129, secondScore: 120, want: draw},
+		{name: "first ahead exactly by threshold", firstScore: 130, secondScore: 120, want: draw},
+		{name: "first ahead just over threshold", firstScore: 131, secondScore: 120, want: win(firstPlayer)},
+		{name: "second ahead exactly by threshold", firstScore: 120, secondScore: 130, want: draw},
+		{name: "second ahead just over threshold", firstScore: 120, secondScore: 131, want: win(secondPlayer)},
+		{name: "both zero", firstScore: 0, secondScore: 0, want: draw},
+		{name: "second did not last at all", firstScore: 300, secondScore: 0, want: win(firstPlayer)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Decide(model.GameStatus{
+				FirstPlayerId:     firstPlayer,
+				SecondPlayerId:    secondPlayer,
+				FirstPlayerScore:  tt.firstScore,
+				SecondPlayerScore: tt.secondScore,
+			})
+			if got != tt.want {
+				t.Fatalf("Decide(%d, %d) = %+v, want %+v", tt.firstScore, tt.secondScore, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScorePolicyZeroThreshold(t *testing.T) {
+	policy := NewScorePolicy(0)
+
+	game := model.GameStatus{FirstPlayerId: firstPlayer, SecondPlayerId: secondPlayer, FirstPlayerScore: 61, SecondPlayerScore: 60}
+	if got := policy.Decide(game); got.WinnerId != firstPlayer || got.Outcome != model.GameOutcomeWin {
+		t.Fatalf("Decide = %+v, want a win for the first player", got)
+	}
+
+	game.SecondPlayerScore = 61
+	if got := policy.Decide(game); got.Outcome != model.GameOutcomeDraw || got.WinnerId != 0 {
+		t.Fatalf("Decide = %+v, want a draw", got)
+	}
+}
+
+func TestJudgedPolicyDecide(t *testing.T) {
+	got := NewJudgedPolicy().Decide(model.GameStatus{
+		FirstPlayerId:     firstPlayer,
+		SecondPlayerId:    secondPlayer,
+		FirstPlayerScore:  300,
+		SecondPlayerScore: 0,
+	})
+	if got != (model.GameVerdict{AwaitingJudge: true}) {
+		t.Fatalf("Decide = %+v, want a verdict awaiting the judge", got)
+	}
+}
@@ -0,0 +1,113 @@
+package imageurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const versionLength = 6
+
+var (
+	ErrInvalidSignature = errors.New("invalid image url signature")
+	ErrExpired          = errors.New("image url expired")
+)
+
+type Config struct {
+	// BaseURL — адрес, с которого клиенты получают аватары: сам сервис или CDN перед ним
+	BaseURL string
+	// Secret включает подпись ссылок. Пустой секрет — ссылки публичные и бессрочные
+	Secret string
+	TTL    time.Duration
+}
+
+// Builder строит ссылки на аватары вида <BaseURL>/user/{id}/image?v=<версия>[&exp=..&sig=..].
+// Версия берётся из хеша изображения, поэтому ссылка меняется вместе с аватаром и её можно кешировать навсегда.
+type Builder struct {
+	config Config
+}
+
+func NewBuilder(config Config) *Builder {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	return &Builder{config: config}
+}
+
+// Signed сообщает, требуются ли подписанные ссылки.
+func (b *Builder) Signed() bool {
+	return b.config.Secret != ""
+}
+
+// UserImageURL возвращает ссылку на аватар пользователя. hash может быть пустым, если изображение не загружено из базы.
+// Пользователям без аватара (imageId == 0) та же ссылка отдаёт сгенерированную картинку, версии у неё нет.
+func (b *Builder) UserImageURL(userId int, imageId int, hash []byte, now time.Time) string {
+	query := url.Values{}
+	if version := Version(hash); imageId != 0 && version != "" {
+		query.Set("v", version)
+	}
+
+	if b.Signed() {
+		exp := b.expiresAt(now)
+		query.Set("exp", strconv.FormatInt(exp.Unix(), 10))
+		query.Set("sig", b.signature(userId, query.Get("v"), exp.Unix()))
+	}
+
+	link := fmt.Sprintf("%s/user/%d/image", b.config.BaseURL, userId)
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// Verify проверяет подпись и срок действия ссылки. Без секрета проверять нечего.
+func (b *Builder) Verify(userId int, query url.Values, now time.Time) error {
+	if !b.Signed() {
+		return nil
+	}
+
+	exp, err := strconv.ParseInt(query.Get("exp"), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := b.signature(userId, query.Get("v"), exp)
+	if !hmac.Equal([]byte(expected), []byte(query.Get("sig"))) {
+		return ErrInvalidSignature
+	}
+	if now.Unix() > exp {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// Version — короткий идентификатор содержимого аватара для ссылок.
+func Version(hash []byte) string {
+	if len(hash) < versionLength {
+		return ""
+	}
+
+	return hex.EncodeToString(hash[:versionLength])
+}
+
+// expiresAt округляет срок действия до половины TTL: в пределах окна ссылка не меняется
+// и браузеры с CDN продолжают попадать в кеш.
+func (b *Builder) expiresAt(now time.Time) time.Time {
+	window := b.config.TTL / 2
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	return now.Truncate(window).Add(b.config.TTL)
+}
+
+func (b *Builder) signature(userId int, version string, exp int64) string {
+	mac := hmac.New(sha256.New, []byte(b.config.Secret))
+	fmt.Fprintf(mac, "%d:%s:%d", userId, version, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
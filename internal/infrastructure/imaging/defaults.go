@@ -0,0 +1,128 @@
+package imaging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultStyleIdenticon = "identicon"
+	DefaultStyleInitials  = "initials"
+
+	FormatSVG = "svg+xml"
+
+	identiconCells = 5
+)
+
+// Identicon рисует симметричный узор 5x5 в PNG. Один и тот же seed всегда даёт одну и ту же картинку.
+func Identicon(seed string, size int) ([]byte, error) {
+	sum := sha256.Sum256([]byte(seed))
+	foreground := seedColor(sum)
+	background := color.NRGBA{R: 240, G: 240, B: 240, A: 255}
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	// Поле в полклетки по краям, чтобы узор не упирался в рамку аватара
+	cell := float64(size) / (identiconCells + 1)
+	margin := cell / 2
+	for row := 0; row < identiconCells; row++ {
+		for col := 0; col < (identiconCells+1)/2; col++ {
+			if sum[row*3+col]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{col, identiconCells - 1 - col} {
+				rect := image.Rect(
+					int(math.Round(margin+float64(c)*cell)),
+					int(math.Round(margin+float64(row)*cell)),
+					int(math.Round(margin+float64(c+1)*cell)),
+					int(math.Round(margin+float64(row+1)*cell)),
+				)
+				draw.Draw(img, rect, &image.Uniform{C: foreground}, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, fmt.Errorf("failed encode identicon: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// InitialsSVG рисует инициалы имени на цветном фоне. Цвет фона определяется seed.
+func InitialsSVG(seed, name string, size int) []byte {
+	c := seedColor(sha256.Sum256([]byte(seed)))
+
+	return []byte(fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 100 100">`+
+			`<rect width="100" height="100" fill="#%02x%02x%02x"/>`+
+			`<text x="50" y="50" dy=".35em" text-anchor="middle" font-family="sans-serif" font-size="42" fill="#ffffff">%s</text>`+
+			`</svg>`,
+		size, size, c.R, c.G, c.B, html.EscapeString(Initials(name)),
+	))
+}
+
+// Initials возвращает до двух первых букв слов имени: "john_doe" → "JD".
+func Initials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var initials []rune
+	for _, word := range words {
+		initials = append(initials, unicode.ToUpper([]rune(word)[0]))
+		if len(initials) == 2 {
+			break
+		}
+	}
+
+	if len(initials) == 0 {
+		return "?"
+	}
+	return string(initials)
+}
+
+// seedColor выбирает насыщенный цвет средней яркости, чтобы на нём читался белый текст.
+func seedColor(sum [sha256.Size]byte) color.NRGBA {
+	hue := float64(int(sum[len(sum)-1])<<8|int(sum[len(sum)-2])) / 65536 * 360
+	return hslToRGB(hue, 0.55, 0.45)
+}
+
+func hslToRGB(h, s, l float64) color.NRGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return color.NRGBA{
+		R: uint8(math.Round((r + m) * 255)),
+		G: uint8(math.Round((g + m) * 255)),
+		B: uint8(math.Round((b + m) * 255)),
+		A: 255,
+	}
+}
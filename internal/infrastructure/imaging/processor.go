@@ -0,0 +1,166 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // Подключаем пакеты для поддерживаемых форматов
+	"image/jpeg"
+	"image/png"
+	"slices"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // WebP на входе
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+
+	jpegQuality = 85
+	// maxPixels защищает от изображений, которые занимают гигабайты после декодирования
+	maxPixels = 40_000_000
+)
+
+// Limits ограничивает принимаемые на загрузку изображения.
+type Limits struct {
+	MaxBytes int64
+	// MaxDimension — предельная ширина и высота в пикселях
+	MaxDimension int
+	// Formats — допустимые форматы в терминах image.DecodeConfig: jpeg, png, gif, webp
+	Formats []string
+}
+
+// Sizes — стороны квадратных вариантов аватара в пикселях
+var Sizes = []int{64, 256, 512}
+
+var (
+	ErrUnsupportedImage  = errors.New("unsupported image")
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrFileTooLarge      = errors.New("image file is too large")
+	ErrImageTooLarge     = errors.New("image dimensions are too large")
+)
+
+type Variant struct {
+	Size   int
+	Format string
+	Data   []byte
+}
+
+// Process декодирует загруженное изображение, учитывает EXIF-ориентацию, обрезает его по центру до квадрата
+// и кодирует в каждый размер из Sizes: в JPEG (PNG, если есть прозрачность) и в WebP.
+// Варианты больше исходного изображения не создаются, вместо них используется сторона оригинала.
+// Все варианты кодируются заново из пикселей, поэтому EXIF, GPS и прочие метаданные оригинала в них не попадают.
+func Process(data []byte, limits Limits) ([]Variant, error) {
+	if err := Check(data, limits); err != nil {
+		return nil, err
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if format == FormatJPEG {
+		src = applyOrientation(src, jpegOrientation(data))
+	}
+
+	square := cropSquare(src)
+	side := square.Bounds().Dx()
+	opaque := isOpaque(square)
+
+	variants := make([]Variant, 0, len(Sizes)*2)
+	for _, size := range Sizes {
+		resized := resize(square, min(size, side))
+
+		primary, primaryFormat, err := encodePrimary(resized, opaque)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{Size: size, Format: primaryFormat, Data: primary})
+
+		webp := &bytes.Buffer{}
+		if err := nativewebp.Encode(webp, resized, nil); err != nil {
+			return nil, fmt.Errorf("failed encode webp: %w", err)
+		}
+		variants = append(variants, Variant{Size: size, Format: FormatWebP, Data: webp.Bytes()})
+	}
+
+	return variants, nil
+}
+
+// Check проверяет размер файла, формат и размеры изображения по заголовку, не декодируя пиксели.
+// Так GIF- и PNG-бомбы отсекаются до того, как займут память.
+func Check(data []byte, limits Limits) error {
+	if limits.MaxBytes > 0 && int64(len(data)) > limits.MaxBytes {
+		return ErrFileTooLarge
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return ErrUnsupportedFormat
+		}
+		return fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if len(limits.Formats) > 0 && !slices.Contains(limits.Formats, format) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	if config.Width <= 0 || config.Height <= 0 {
+		return ErrUnsupportedImage
+	}
+	if limits.MaxDimension > 0 && (config.Width > limits.MaxDimension || config.Height > limits.MaxDimension) {
+		return ErrImageTooLarge
+	}
+	if config.Width*config.Height > maxPixels {
+		return ErrImageTooLarge
+	}
+
+	return nil
+}
+
+func cropSquare(src image.Image) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, image.Point{X: x0, Y: y0}, draw.Src)
+
+	return dst
+}
+
+func resize(src image.Image, side int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, side, side))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	return dst
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+
+	return false
+}
+
+func encodePrimary(img image.Image, opaque bool) ([]byte, string, error) {
+	buf := &bytes.Buffer{}
+	if opaque {
+		if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, "", fmt.Errorf("failed encode jpeg: %w", err)
+		}
+		return buf.Bytes(), FormatJPEG, nil
+	}
+
+	if err := png.Encode(buf, img); err != nil {
+		return nil, "", fmt.Errorf("failed encode png: %w", err)
+	}
+	return buf.Bytes(), FormatPNG, nil
+}
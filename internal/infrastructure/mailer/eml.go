@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/smtp"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.Mailer = (*EMLMailer)(nil)
+)
+
+// EMLMailer складывает письма .eml-файлами в директорию, их можно открыть любым почтовым клиентом.
+type EMLMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+func NewEMLMailer(dir string, from string) (*EMLMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, tracerr.Errorf("failed create mail directory: %w", err)
+	}
+
+	return &EMLMailer{dir: dir, from: from}, nil
+}
+
+func (m *EMLMailer) Send(ctx context.Context, message *model.MailMessage) error {
+	msg := smtp.NewMessage(m.from, message)
+	msg.SetDateHeader("Date", time.Now())
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), m.seq.Add(1))
+	tmp, err := os.CreateTemp(m.dir, ".eml-*")
+	if err != nil {
+		return tracerr.Errorf("failed write eml: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := msg.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return tracerr.Errorf("failed write eml: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return tracerr.Errorf("failed write eml: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(m.dir, name)); err != nil {
+		return tracerr.Errorf("failed write eml: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"go.uber.org/zap"
+)
+
+var (
+	_ repo.Mailer = (*LogMailer)(nil)
+)
+
+// LogMailer ничего не отправляет, а пишет письма в лог. Текстовая версия попадает в лог целиком,
+// чтобы при локальной разработке было видно коды подтверждения.
+type LogMailer struct {
+	logger *zap.Logger
+}
+
+func NewLogMailer(logger *zap.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message *model.MailMessage) error {
+	m.logger.Info("email",
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+		zap.String("unsubscribe", message.Unsubscribe),
+		zap.String("text", message.Text),
+	)
+
+	return nil
+}
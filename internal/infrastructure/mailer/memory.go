@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+)
+
+var (
+	_ repo.Mailer = (*MemoryMailer)(nil)
+)
+
+// MemoryMailer запоминает отправленные письма, чтобы тесты могли их проверить.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []model.MailMessage
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, message *model.MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, *message)
+	return nil
+}
+
+// Messages возвращает копию отправленных писем в порядке отправки.
+func (m *MemoryMailer) Messages() []model.MailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]model.MailMessage(nil), m.messages...)
+}
+
+// Last возвращает последнее письмо на адрес to.
+func (m *MemoryMailer) Last(to string) (model.MailMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+
+	return model.MailMessage{}, false
+}
+
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
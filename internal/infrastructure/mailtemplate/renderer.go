@@ -0,0 +1,191 @@
+package mailtemplate
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Шаблоны писем: templates/<локаль>/<имя>.txt задаёт блоки "subject" и "text",
+// <имя>.html — блок "content", который встраивается в layout.html той же локали.
+//
+//go:embed templates
+var templatesFS embed.FS
+
+const (
+	MessagePasswordRecovery = "password_recovery"
+	MessageEmailChangeCode  = "email_change_code"
+	MessageEmailChanged     = "email_changed"
+
+	MessageAchievementUnlocked = "achievement_unlocked"
+	MessageTopicApproved       = "topic_approved"
+	MessageTopicDeclined       = "topic_declined"
+	MessageGameFinished        = "game_finished"
+	MessageGameChallenge       = "game_challenge"
+)
+
+// PasswordRecoveryData — данные письма с кодом восстановления пароля.
+type PasswordRecoveryData struct {
+	Code       string
+	TTLMinutes int
+}
+
+// EmailChangeCodeData — данные письма с кодом подтверждения новой почты.
+type EmailChangeCodeData struct {
+	Code       string
+	TTLMinutes int
+}
+
+// EmailChangedData — данные уведомления на старую почту о её смене.
+type EmailChangedData struct {
+	NewEmail        string
+	RevertLink      string
+	RevertExpiresAt string
+}
+
+// AchievementUnlockedData — данные письма о полученной ачивке.
+type AchievementUnlockedData struct {
+	Name        string
+	Description string
+}
+
+// TopicDecisionData — данные письма автору темы о решении модератора.
+type TopicDecisionData struct {
+	TopicName string
+}
+
+// GameFinishedData — данные письма игроку об итоге игры. Outcome — значение GameOutcomeEnum.
+type GameFinishedData struct {
+	Outcome string
+	Won     bool
+}
+
+// GameChallengeData — данные письма о вызове на игру.
+type GameChallengeData struct {
+	ChallengerName string
+}
+
+// Branding — то, что одинаково во всех письмах.
+type Branding struct {
+	ProductName  string
+	BaseURL      string
+	SupportEmail string
+}
+
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type templateData struct {
+	Locale      string
+	Subject     string
+	Unsubscribe string
+	Brand       Branding
+	Data        interface{}
+}
+
+type templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+type Renderer struct {
+	brand         Branding
+	defaultLocale string
+	templates     map[string]map[string]*templates
+}
+
+// NewRenderer разбирает все встроенные шаблоны заранее, чтобы ошибка в шаблоне ломала запуск, а не отправку письма.
+func NewRenderer(brand Branding, defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		brand:         brand,
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]map[string]*templates),
+	}
+
+	locales, err := fs.ReadDir(templatesFS, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed read mail templates: %w", err)
+	}
+
+	for _, locale := range locales {
+		dir := path.Join("templates", locale.Name())
+		files, err := fs.Glob(templatesFS, path.Join(dir, "*.txt"))
+		if err != nil {
+			return nil, fmt.Errorf("failed read mail templates: %w", err)
+		}
+
+		r.templates[locale.Name()] = make(map[string]*templates)
+		for _, file := range files {
+			name := strings.TrimSuffix(path.Base(file), ".txt")
+			if name == "layout" {
+				continue
+			}
+
+			text, err := texttemplate.ParseFS(templatesFS, path.Join(dir, "layout.txt"), file)
+			if err != nil {
+				return nil, fmt.Errorf("failed parse mail template %s: %w", file, err)
+			}
+			html, err := htmltemplate.ParseFS(templatesFS, path.Join(dir, "layout.html"), path.Join(dir, name+".html"))
+			if err != nil {
+				return nil, fmt.Errorf("failed parse mail template %s: %w", file, err)
+			}
+
+			r.templates[locale.Name()][name] = &templates{text: text, html: html}
+		}
+	}
+
+	if _, ok := r.templates[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no mail templates for default locale %q", defaultLocale)
+	}
+
+	return r, nil
+}
+
+func (r *Renderer) DefaultLocale() string {
+	return r.defaultLocale
+}
+
+// Render собирает письмо name на языке locale. Если перевода нет, используется локаль по умолчанию.
+// Непустая ссылка unsubscribe выводится в подвале письма.
+func (r *Renderer) Render(name string, locale string, unsubscribe string, data interface{}) (*Message, error) {
+	tmpl, ok := r.templates[locale][name]
+	if !ok {
+		locale = r.defaultLocale
+		tmpl, ok = r.templates[locale][name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown mail template %q", name)
+	}
+
+	td := templateData{Locale: locale, Unsubscribe: unsubscribe, Brand: r.brand, Data: data}
+
+	subject := &bytes.Buffer{}
+	if err := tmpl.text.ExecuteTemplate(subject, "subject", td); err != nil {
+		return nil, fmt.Errorf("failed render mail subject %s: %w", name, err)
+	}
+	td.Subject = strings.TrimSpace(subject.String())
+
+	text := &bytes.Buffer{}
+	if err := tmpl.text.ExecuteTemplate(text, "layout", td); err != nil {
+		return nil, fmt.Errorf("failed render mail text %s: %w", name, err)
+	}
+
+	html := &bytes.Buffer{}
+	if err := tmpl.html.ExecuteTemplate(html, "layout", td); err != nil {
+		return nil, fmt.Errorf("failed render mail html %s: %w", name, err)
+	}
+
+	return &Message{
+		Subject: td.Subject,
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package password
+
+import (
+	"errors"
+
+	"github.com/ztrue/tracerr"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptHasher struct {
+	cost int
+}
+
+func newBcryptHasher(cost int) *bcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+
+	return &bcryptHasher{cost: cost}
+}
+
+func (h *bcryptHasher) algorithm() Algorithm {
+	return AlgorithmBcrypt
+}
+
+func (h *bcryptHasher) matches(encoded string) bool {
+	return hasPrefix(encoded, "$2a$", "$2b$", "$2y$")
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", tracerr.Wrap(err)
+	}
+
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(encoded, password string) (bool, bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, tracerr.Errorf("%w: %v", ErrUnknownHash, err)
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return false, false, tracerr.Wrap(err)
+	}
+
+	return true, cost != h.cost, nil
+}
@@ -0,0 +1,119 @@
+package password
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ztrue/tracerr"
+)
+
+// lineProbeSize — сколько байт читается за раз при поиске конца строки. Строка корпуса
+// "<SHA1>:<количество>" обычно укладывается в одно чтение.
+const lineProbeSize = 128
+
+// BreachedCorpus - локальная база утёкших паролей в формате Have I Been Pwned "ordered by hash":
+// строки вида "<SHA1 в hex>:<количество>", отсортированные по хешу. Файл не загружается в память,
+// каждая проверка — двоичный поиск по нему, поэтому подходит и полный корпус в десятки гигабайт.
+type BreachedCorpus struct {
+	file *os.File
+	size int64
+}
+
+func LoadBreachedCorpus(path string) (*BreachedCorpus, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, tracerr.Errorf("can't open breached passwords corpus: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, tracerr.Errorf("can't read breached passwords corpus: %w", err)
+	}
+
+	return &BreachedCorpus{
+		file: file,
+		size: info.Size(),
+	}, nil
+}
+
+// Contains ищет пароль в корпусе. Ошибка чтения файла не должна мешать пользователю сменить
+// пароль, поэтому такой пароль считается не найденным.
+func (c *BreachedCorpus) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Искомая строка, если она есть, начинается в [lo, hi). lo всегда указывает на начало строки
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start := mid
+		if mid > lo {
+			newline, err := c.nextNewline(mid - 1)
+			if err != nil {
+				return false
+			}
+			start = newline + 1
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		end, err := c.nextNewline(start)
+		if err != nil {
+			return false
+		}
+		line, err := c.read(start, end)
+		if err != nil {
+			return false
+		}
+
+		lineHash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		switch strings.Compare(strings.ToUpper(lineHash), hash) {
+		case 0:
+			return true
+		case -1:
+			lo = end + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return false
+}
+
+// nextNewline — смещение первого перевода строки не раньше from или размер файла, если его нет.
+func (c *BreachedCorpus) nextNewline(from int64) (int64, error) {
+	buf := make([]byte, lineProbeSize)
+	for from < c.size {
+		n, err := c.file.ReadAt(buf, from)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return from + int64(i), nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if n == 0 {
+			break
+		}
+		from += int64(n)
+	}
+
+	return c.size, nil
+}
+
+func (c *BreachedCorpus) read(start, end int64) (string, error) {
+	buf := make([]byte, end-start)
+	n, err := c.file.ReadAt(buf, start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return string(buf[:n]), nil
+}
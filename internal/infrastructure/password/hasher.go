@@ -0,0 +1,100 @@
+package password
+
+import (
+	"strings"
+
+	"github.com/ztrue/tracerr"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmArgon2id Algorithm = "argon2id"
+	AlgorithmBcrypt   Algorithm = "bcrypt"
+)
+
+var (
+	ErrUnknownHash = tracerr.New("unknown password hash format")
+)
+
+// Hasher хеширует пароли и проверяет их. Хеш всегда содержит идентификатор алгоритма
+// и параметры, поэтому старые хеши продолжают проверяться после смены алгоритма.
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Verify возвращает needsRehash = true, если пароль верный, но хеш сделан
+	// не текущим алгоритмом или с устаревшими параметрами.
+	Verify(encoded, password string) (ok bool, needsRehash bool, err error)
+}
+
+type algorithmHasher interface {
+	Hasher
+	algorithm() Algorithm
+	matches(encoded string) bool
+}
+
+type HasherConfig struct {
+	Algorithm  Algorithm
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+// MultiHasher хеширует новые пароли алгоритмом по умолчанию, а проверяет
+// любым из известных алгоритмов, определяя его по префиксу хеша.
+type MultiHasher struct {
+	current    algorithmHasher
+	algorithms []algorithmHasher
+}
+
+var (
+	_ Hasher = (*MultiHasher)(nil)
+)
+
+func NewHasher(cfg HasherConfig) (*MultiHasher, error) {
+	argon := newArgon2idHasher(cfg.Argon2id)
+	bcryptHasher := newBcryptHasher(cfg.BcryptCost)
+
+	hasher := &MultiHasher{
+		algorithms: []algorithmHasher{argon, bcryptHasher},
+	}
+
+	switch cfg.Algorithm {
+	case AlgorithmArgon2id, "":
+		hasher.current = argon
+	case AlgorithmBcrypt:
+		hasher.current = bcryptHasher
+	default:
+		return nil, tracerr.Errorf("unknown password hash algorithm %q", cfg.Algorithm)
+	}
+
+	return hasher, nil
+}
+
+func (h *MultiHasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *MultiHasher) Verify(encoded, password string) (bool, bool, error) {
+	for _, algorithm := range h.algorithms {
+		if !algorithm.matches(encoded) {
+			continue
+		}
+
+		ok, needsRehash, err := algorithm.Verify(encoded, password)
+		if err != nil || !ok {
+			return false, false, err
+		}
+
+		return true, needsRehash || algorithm.algorithm() != h.current.algorithm(), nil
+	}
+
+	return false, false, ErrUnknownHash
+}
+
+func hasPrefix(encoded string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(encoded, prefix) {
+			return true
+		}
+	}
+	return false
+}
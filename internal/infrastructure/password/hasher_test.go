@@ -0,0 +1,165 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Минимальные параметры, чтобы тесты не тратили время на само хеширование
+var (
+	testArgon2Params = Argon2idParams{MemoryKiB: 64, Iterations: 1, Parallelism: 1}
+	testBcryptCost   = bcrypt.MinCost
+)
+
+func newTestHasher(t *testing.T, algorithm Algorithm, argon Argon2idParams, bcryptCost int) *MultiHasher {
+	hasher, err := NewHasher(HasherConfig{
+		Algorithm:  algorithm,
+		Argon2id:   argon,
+		BcryptCost: bcryptCost,
+	})
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+	return hasher
+}
+
+func hashWith(t *testing.T, hasher Hasher, password string) string {
+	encoded, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	return encoded
+}
+
+func TestHasherVerify(t *testing.T) {
+	argon := newTestHasher(t, AlgorithmArgon2id, testArgon2Params, testBcryptCost)
+	bcryptCurrent := newTestHasher(t, AlgorithmBcrypt, testArgon2Params, testBcryptCost)
+
+	argonHash := hashWith(t, argon, "s3cret-pass")
+	bcryptHash := hashWith(t, bcryptCurrent, "s3cret-pass")
+
+	if !strings.HasPrefix(argonHash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("argon2id hash %q is not in PHC format", argonHash)
+	}
+	if !strings.HasPrefix(bcryptHash, "$2a$") {
+		t.Fatalf("bcrypt hash %q has unexpected prefix", bcryptHash)
+	}
+
+	tests := []struct {
+		name            string
+		hasher          *MultiHasher
+		encoded         string
+		password        string
+		wantOk          bool
+		wantNeedsRehash bool
+	}{
+		{
+			name:     "argon2id correct password",
+			hasher:   argon,
+			encoded:  argonHash,
+			password: "s3cret-pass",
+			wantOk:   true,
+		},
+		{
+			name:     "argon2id wrong password",
+			hasher:   argon,
+			encoded:  argonHash,
+			password: "s3cret-pasS",
+		},
+		{
+			name:     "bcrypt correct password",
+			hasher:   bcryptCurrent,
+			encoded:  bcryptHash,
+			password: "s3cret-pass",
+			wantOk:   true,
+		},
+		{
+			name:     "bcrypt wrong password",
+			hasher:   bcryptCurrent,
+			encoded:  bcryptHash,
+			password: "wrong",
+		},
+		{
+			name:            "bcrypt hash is upgraded to argon2id",
+			hasher:          argon,
+			encoded:         bcryptHash,
+			password:        "s3cret-pass",
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:     "wrong password is never rehashed",
+			hasher:   argon,
+			encoded:  bcryptHash,
+			password: "wrong",
+		},
+		{
+			name:            "argon2id with outdated params",
+			hasher:          newTestHasher(t, AlgorithmArgon2id, Argon2idParams{MemoryKiB: 128, Iterations: 1, Parallelism: 1}, testBcryptCost),
+			encoded:         argonHash,
+			password:        "s3cret-pass",
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "bcrypt with outdated cost",
+			hasher:          newTestHasher(t, AlgorithmBcrypt, testArgon2Params, testBcryptCost+1),
+			encoded:         bcryptHash,
+			password:        "s3cret-pass",
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := tt.hasher.Verify(tt.encoded, tt.password)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if ok != tt.wantOk || needsRehash != tt.wantNeedsRehash {
+				t.Fatalf("Verify = (%v, %v), want (%v, %v)", ok, needsRehash, tt.wantOk, tt.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestHasherVerifyMalformedHash(t *testing.T) {
+	hasher := newTestHasher(t, AlgorithmArgon2id, testArgon2Params, testBcryptCost)
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "empty", encoded: ""},
+		{name: "plain text", encoded: "s3cret-pass"},
+		{name: "unknown algorithm", encoded: "$scrypt$ln=16,r=8,p=1$c2FsdA$aGFzaA"},
+		{name: "argon2id missing parts", encoded: "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ"},
+		{name: "argon2id unsupported version", encoded: "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$aGFzaGhhc2g"},
+		{name: "argon2id zero parallelism", encoded: "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHQ$aGFzaGhhc2g"},
+		{name: "argon2id empty key", encoded: "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$"},
+		{name: "argon2id bad base64", encoded: "$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaGhhc2g"},
+		{name: "truncated bcrypt", encoded: "$2a$04$tooshort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := hasher.Verify(tt.encoded, "s3cret-pass")
+			if !errors.Is(err, ErrUnknownHash) {
+				t.Fatalf("Verify err = %v, want ErrUnknownHash", err)
+			}
+			if ok || needsRehash {
+				t.Fatalf("Verify = (%v, %v), want (false, false)", ok, needsRehash)
+			}
+		})
+	}
+}
+
+func TestNewHasherUnknownAlgorithm(t *testing.T) {
+	if _, err := NewHasher(HasherConfig{Algorithm: "md5"}); err == nil {
+		t.Fatal("NewHasher accepted an unknown algorithm")
+	}
+}
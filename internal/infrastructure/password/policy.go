@@ -0,0 +1,73 @@
+package password
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+)
+
+const (
+	// Совпадения с короткими именами дают слишком много ложных срабатываний
+	minIdentityPartLength = 3
+)
+
+type PolicyConfig struct {
+	MinLength          int
+	BreachedCorpusFile string
+}
+
+type Policy struct {
+	minLength int
+	breached  *BreachedCorpus
+}
+
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	policy := &Policy{
+		minLength: cfg.MinLength,
+	}
+
+	if cfg.BreachedCorpusFile != "" {
+		corpus, err := LoadBreachedCorpus(cfg.BreachedCorpusFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.breached = corpus
+	}
+
+	return policy, nil
+}
+
+// Check возвращает список нарушений политики. Пустой список - пароль подходит.
+func (p *Policy) Check(password, username, email string) []model.PasswordViolationEnum {
+	var violations []model.PasswordViolationEnum
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		violations = append(violations, model.PasswordViolationTooShort)
+	}
+
+	lowerPassword := strings.ToLower(password)
+	if containsIdentity(lowerPassword, username) {
+		violations = append(violations, model.PasswordViolationContainsUsername)
+	}
+
+	localPart, _, _ := strings.Cut(email, "@")
+	if containsIdentity(lowerPassword, email) || containsIdentity(lowerPassword, localPart) {
+		violations = append(violations, model.PasswordViolationContainsEmail)
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		violations = append(violations, model.PasswordViolationBreached)
+	}
+
+	return violations
+}
+
+func containsIdentity(lowerPassword, identity string) bool {
+	identity = strings.ToLower(strings.TrimSpace(identity))
+	if utf8.RuneCountInString(identity) < minIdentityPartLength {
+		return false
+	}
+
+	return strings.Contains(lowerPassword, identity)
+}
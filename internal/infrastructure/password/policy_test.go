@@ -0,0 +1,150 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+)
+
+// writeCorpus записывает корпус в формате Have I Been Pwned: отсортированные строки "<SHA1>:<количество>".
+func writeCorpus(t *testing.T, passwords ...string) string {
+	lines := make([]string, 0, len(passwords))
+	for i, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":"+strconv.Itoa(i+1))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatalf("write corpus: %v", err)
+	}
+	return path
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy, err := NewPolicy(PolicyConfig{
+		MinLength:          8,
+		BreachedCorpusFile: writeCorpus(t, "password123", "qwertyuiop", "letmein!!"),
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		username string
+		email    string
+		want     []model.PasswordViolationEnum
+	}{
+		{
+			name:     "strong password",
+			password: "correct horse battery",
+			username: "alice",
+			email:    "alice@example.com",
+		},
+		{
+			name:     "too short",
+			password: "x7#kq",
+			username: "alice",
+			email:    "alice@example.com",
+			want:     []model.PasswordViolationEnum{model.PasswordViolationTooShort},
+		},
+		{
+			name:     "length is counted in runes",
+			password: "пароль№1",
+			username: "alice",
+			email:    "alice@example.com",
+		},
+		{
+			name:     "contains username in another case",
+			password: "my-ALICE-secret",
+			username: "Alice",
+			email:    "someone@example.com",
+			want:     []model.PasswordViolationEnum{model.PasswordViolationContainsUsername},
+		},
+		{
+			name:     "contains email local part",
+			password: "bob.smith-2024",
+			username: "robert",
+			email:    "bob.smith@example.com",
+			want:     []model.PasswordViolationEnum{model.PasswordViolationContainsEmail},
+		},
+		{
+			name:     "short identities are ignored",
+			password: "jo-and-friends",
+			username: "jo",
+			email:    "jo@example.com",
+		},
+		{
+			name:     "breached",
+			password: "qwertyuiop",
+			username: "alice",
+			email:    "alice@example.com",
+			want:     []model.PasswordViolationEnum{model.PasswordViolationBreached},
+		},
+		{
+			name:     "several violations",
+			password: "alice",
+			username: "alice",
+			email:    "alice@example.com",
+			want: []model.PasswordViolationEnum{
+				model.PasswordViolationTooShort,
+				model.PasswordViolationContainsUsername,
+				model.PasswordViolationContainsEmail,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Check(tt.password, tt.username, tt.email)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Check(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreachedCorpusContains(t *testing.T) {
+	breached := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		breached = append(breached, "leaked-"+strconv.Itoa(i))
+	}
+
+	corpus, err := LoadBreachedCorpus(writeCorpus(t, breached...))
+	if err != nil {
+		t.Fatalf("LoadBreachedCorpus: %v", err)
+	}
+
+	for _, password := range breached {
+		if !corpus.Contains(password) {
+			t.Fatalf("Contains(%q) = false, want true", password)
+		}
+	}
+	for i := 0; i < 200; i++ {
+		if password := "safe-" + strconv.Itoa(i); corpus.Contains(password) {
+			t.Fatalf("Contains(%q) = true, want false", password)
+		}
+	}
+}
+
+func TestBreachedCorpusEmpty(t *testing.T) {
+	corpus, err := LoadBreachedCorpus(writeCorpus(t))
+	if err != nil {
+		t.Fatalf("LoadBreachedCorpus: %v", err)
+	}
+
+	if corpus.Contains("anything") {
+		t.Fatal("empty corpus contains a password")
+	}
+}
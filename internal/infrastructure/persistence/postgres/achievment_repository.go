@@ -6,6 +6,7 @@ import (
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/go-pg/pg/v9"
+	"github.com/ztrue/tracerr"
 )
 
 type AchievementRepository struct {
@@ -36,3 +37,25 @@ func (r *AchievementRepository) GetAchievmentsByUserId(ctx context.Context, user
 
 	return achievements, nil
 }
+
+func (r *AchievementRepository) AwardWinAchievements(ctx context.Context, userId int) ([]*model.Achievements, error) {
+	var awarded []*model.Achievements
+	_, err := r.db.QueryContext(ctx, &awarded, `
+		WITH wins AS (
+			SELECT count(*) AS amount FROM games WHERE winner_id = ?0
+		), inserted AS (
+			INSERT INTO users_achievements (user_id, achievement_id)
+			SELECT ?0, a.id FROM achievements a, wins
+			WHERE a.wins_required IS NOT NULL AND a.wins_required <= wins.amount
+			ON CONFLICT ON CONSTRAINT unique_users_achievements DO NOTHING
+			RETURNING achievement_id
+		)
+		SELECT a.* FROM achievements a JOIN inserted i ON i.achievement_id = a.id`,
+		userId,
+	)
+	if err != nil {
+		return nil, tracerr.Errorf("failed award achievements: %w", err)
+	}
+
+	return awarded, nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.AuditRepository = (*AuditRepository)(nil)
+)
+
+type AuditRepository struct {
+	db *pg.DB
+}
+
+func NewAuditRepository(db *pg.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (a *AuditRepository) CreateEvent(ctx context.Context, event *model.AuditEvent) error {
+	if _, err := a.db.ModelContext(ctx, event).Insert(); err != nil {
+		return tracerr.Errorf("failed insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AuditRepository) GetEvents(ctx context.Context, filter model.AuditEventFilter, afterId *int, limit int) ([]model.AuditEvent, error) {
+	var events []model.AuditEvent
+
+	q := a.db.ModelContext(ctx, &events)
+	if filter.ActorID != nil {
+		q = q.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.ImpersonatorID != nil {
+		q = q.Where("impersonator_id = ?", *filter.ImpersonatorID)
+	}
+	if filter.Action != nil {
+		q = q.Where("action = ?", *filter.Action)
+	}
+	if filter.TargetType != nil {
+		q = q.Where("target_type = ?", *filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		q = q.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.From != nil {
+		q = q.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("created_at < ?", *filter.To)
+	}
+	if afterId != nil {
+		q = q.Where("id < ?", *afterId)
+	}
+
+	err := q.Order("id DESC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, tracerr.Errorf("failed get audit events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.BlobStore = (*BlobStore)(nil)
+)
+
+// BlobStore хранит блобы в таблице blobs той же базы.
+type BlobStore struct {
+	db *pg.DB
+}
+
+func NewBlobStore(db *pg.DB) *BlobStore {
+	return &BlobStore{db: db}
+}
+
+func (b *BlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := b.db.ExecContext(ctx, `
+		INSERT INTO blobs (key, content_type, data) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET content_type = excluded.content_type, data = excluded.data`,
+		key, contentType, data)
+	if err != nil {
+		return tracerr.Errorf("failed put blob: %w", err)
+	}
+
+	return nil
+}
+
+func (b *BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	_, err := b.db.QueryOneContext(ctx, pg.Scan(&data), `SELECT data FROM blobs WHERE key = ?`, key)
+	if err != nil {
+		if isNoRowsError(err) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Errorf("failed get blob: %w", err)
+	}
+
+	return data, nil
+}
+
+func (b *BlobStore) Delete(ctx context.Context, key string) error {
+	if _, err := b.db.ExecContext(ctx, `DELETE FROM blobs WHERE key = ?`, key); err != nil {
+		return tracerr.Errorf("failed delete blob: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.EmailChangeRepository = (*EmailChangeRepository)(nil)
+)
+
+type EmailChangeRepository struct {
+	db *pg.DB
+}
+
+func NewEmailChangeRepository(db *pg.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{
+		db: db,
+	}
+}
+
+func (e *EmailChangeRepository) CreateEmailChange(ctx context.Context, change *model.EmailChange, email *model.OutboxEmail) (*model.EmailChange, error) {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return nil, tracerr.Errorf("failed create email change: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ModelContext(ctx, &model.EmailChange{}).
+		Where("user_id = ?", change.UserID).
+		Where("confirmed_at IS NULL").
+		Delete()
+	if err != nil {
+		return nil, tracerr.Errorf("failed delete previous email change: %w", err)
+	}
+
+	if _, err = tx.ModelContext(ctx, change).Insert(); err != nil {
+		return nil, tracerr.Errorf("failed create email change: %w", err)
+	}
+
+	if err = enqueueEmail(ctx, tx, email); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, tracerr.Errorf("failed create email change: %w", err)
+	}
+
+	return change, nil
+}
+
+func (e *EmailChangeRepository) FindPendingEmailChange(ctx context.Context, userId int, code string) (*model.EmailChange, error) {
+	change := &model.EmailChange{}
+	err := e.db.ModelContext(ctx, change).
+		Where("user_id = ?", userId).
+		Where("code = ?", code).
+		Where("confirmed_at IS NULL").
+		Where("expired_at > now()").
+		Select()
+	if err != nil {
+		if isNoRowsError(err) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Errorf("failed find email change: %w", err)
+	}
+
+	return change, nil
+}
+
+func (e *EmailChangeRepository) FindEmailChangeByRevertToken(ctx context.Context, token string) (*model.EmailChange, error) {
+	change := &model.EmailChange{}
+	err := e.db.ModelContext(ctx, change).
+		Where("revert_token = ?", token).
+		Where("confirmed_at IS NOT NULL").
+		Where("reverted_at IS NULL").
+		Where("revert_expired_at > now()").
+		Select()
+	if err != nil {
+		if isNoRowsError(err) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Errorf("failed find email change: %w", err)
+	}
+
+	return change, nil
+}
+
+func (e *EmailChangeRepository) ConfirmEmailChange(ctx context.Context, change *model.EmailChange, email *model.OutboxEmail) error {
+	return e.switchEmail(ctx, change, change.OldEmail, change.NewEmail, email, func(now time.Time) []string {
+		change.ConfirmedAt = &now
+		return []string{"confirmed_at", "revert_expired_at"}
+	})
+}
+
+func (e *EmailChangeRepository) RevertEmailChange(ctx context.Context, change *model.EmailChange) error {
+	return e.switchEmail(ctx, change, change.NewEmail, change.OldEmail, nil, func(now time.Time) []string {
+		change.RevertedAt = &now
+		return []string{"reverted_at"}
+	})
+}
+
+// switchEmail в одной транзакции меняет почту пользователя с from на to и отмечает заявку.
+// Коды восстановления удаляются: они были отправлены на адрес, который больше не принадлежит пользователю.
+// Если передано письмо, оно ставится в очередь в той же транзакции.
+func (e *EmailChangeRepository) switchEmail(
+	ctx context.Context,
+	change *model.EmailChange,
+	from, to string,
+	email *model.OutboxEmail,
+	mark func(now time.Time) []string,
+) error {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return tracerr.Errorf("failed switch email: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ModelContext(ctx, &model.RecoveryCode{}).
+		Where("email = ?", from).
+		Delete()
+	if err != nil {
+		return tracerr.Errorf("failed delete recovery codes: %w", err)
+	}
+
+	now := time.Now().UTC()
+	result, err := tx.ModelContext(ctx, &model.User{}).
+		Set("email = ?", to).
+		Set("updated_at = ?", now).
+		Where("id = ?", change.UserID).
+		Where("email = ?", from).
+		Update()
+	if err != nil {
+		if getConstraint(err) != "" {
+			return repo.ErrAlreadyExist
+		}
+		return tracerr.Errorf("failed switch email: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	_, err = tx.ModelContext(ctx, change).
+		Column(mark(now)...).
+		WherePK().
+		Update()
+	if err != nil {
+		return tracerr.Errorf("failed update email change: %w", err)
+	}
+
+	if err = enqueueEmail(ctx, tx, email); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return tracerr.Errorf("failed switch email: %w", err)
+	}
+
+	return nil
+}
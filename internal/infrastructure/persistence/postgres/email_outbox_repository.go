@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/go-pg/pg/v9/orm"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.EmailOutboxRepository = (*EmailOutboxRepository)(nil)
+)
+
+type EmailOutboxRepository struct {
+	db *pg.DB
+}
+
+func NewEmailOutboxRepository(db *pg.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{
+		db: db,
+	}
+}
+
+// enqueueEmail ставит письмо в очередь в транзакции вызывающего. nil — письма нет.
+func enqueueEmail(ctx context.Context, tx orm.DB, email *model.OutboxEmail) error {
+	if email == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	email.Status = model.EmailStatusPending
+	email.Attempts = 0
+	email.NextAttemptAt = now
+	email.CreatedAt = now
+
+	if _, err := tx.ModelContext(ctx, email).Insert(); err != nil {
+		return tracerr.Errorf("failed enqueue email: %w", err)
+	}
+
+	return nil
+}
+
+func (e *EmailOutboxRepository) EnqueueEmail(ctx context.Context, email *model.OutboxEmail) error {
+	return enqueueEmail(ctx, e.db, email)
+}
+
+func (e *EmailOutboxRepository) ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]*model.OutboxEmail, error) {
+	var emails []*model.OutboxEmail
+	_, err := e.db.QueryContext(ctx, &emails, `
+		UPDATE email_outbox
+		SET next_attempt_at = now() + ? * interval '1 second'
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = ? AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		int(lease/time.Second), model.EmailStatusPending, limit,
+	)
+	if err != nil {
+		return nil, tracerr.Errorf("failed claim emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+func (e *EmailOutboxRepository) MarkEmailSent(ctx context.Context, id int) error {
+	_, err := e.db.ModelContext(ctx, &model.OutboxEmail{}).
+		Set("status = ?", model.EmailStatusSent).
+		Set("attempts = attempts + 1").
+		Set("sent_at = now()").
+		Set("last_error = NULL").
+		Where("id = ?", id).
+		Update()
+	if err != nil {
+		return tracerr.Errorf("failed mark email sent: %w", err)
+	}
+
+	return nil
+}
+
+func (e *EmailOutboxRepository) MarkEmailFailed(ctx context.Context, email *model.OutboxEmail) error {
+	_, err := e.db.ModelContext(ctx, email).
+		Column("status", "attempts", "next_attempt_at", "last_error").
+		WherePK().
+		Update()
+	if err != nil {
+		return tracerr.Errorf("failed mark email failed: %w", err)
+	}
+
+	return nil
+}
+
+func (e *EmailOutboxRepository) GetEmails(
+	ctx context.Context,
+	statuses []model.EmailStatusEnum,
+	limit int,
+	offset int,
+) ([]*model.OutboxEmail, error) {
+	var emails []*model.OutboxEmail
+	q := e.db.ModelContext(ctx, &emails).
+		Order("created_at DESC", "id DESC").
+		Limit(limit).
+		Offset(offset)
+	if len(statuses) != 0 {
+		q = q.Where("status IN (?)", pg.In(statuses))
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, tracerr.Errorf("failed get emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+func (e *EmailOutboxRepository) RetryEmail(ctx context.Context, id int) (*model.OutboxEmail, error) {
+	email := &model.OutboxEmail{}
+	result, err := e.db.ModelContext(ctx, email).
+		Set("status = ?", model.EmailStatusPending).
+		Set("attempts = 0").
+		Set("next_attempt_at = now()").
+		Where("id = ?", id).
+		Where("status = ?", model.EmailStatusDead).
+		Where("expires_at IS NULL OR expires_at > now()").
+		Where("redacted_at IS NULL").
+		Returning("*").
+		Update()
+	if err != nil {
+		return nil, tracerr.Errorf("failed retry email: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, repo.ErrNotFound
+	}
+
+	return email, nil
+}
+
+func (e *EmailOutboxRepository) RedactEmails(ctx context.Context, before time.Time) (int, error) {
+	result, err := e.db.ModelContext(ctx, (*model.OutboxEmail)(nil)).
+		Set("text_body = ''").
+		Set("html_body = ''").
+		Set("list_unsubscribe = NULL").
+		Set("redacted_at = now()").
+		Where("status IN (?)", pg.In([]model.EmailStatusEnum{model.EmailStatusSent, model.EmailStatusDead})).
+		Where("created_at < ?", before).
+		Where("redacted_at IS NULL").
+		Update()
+	if err != nil {
+		return 0, tracerr.Errorf("failed redact emails: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -2,13 +2,13 @@ package postgres
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"errors"
 	"time"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/go-pg/pg/v9"
+	"github.com/go-pg/pg/v9/orm"
 	"github.com/ztrue/tracerr"
 )
 
@@ -19,44 +19,126 @@ var (
 const (
 	gameDuration    = time.Second * 40
 	waitingDuration = time.Second * 20
+	// scoreTolerance — запас на задержку сети при сверке результата игрока с часами сервера
+	scoreTolerance = time.Second * 2
 )
 
+// outcomeResultTexts — итог для исходов без победы по аргументам
+var outcomeResultTexts = map[model.GameOutcomeEnum]string{
+	model.GameOutcomeDraw:      "Ничья: аргументы обоих участников оказались одинаково убедительными.",
+	model.GameOutcomeForfeit:   "Соперник покинул дебаты, победа засчитана оставшемуся участнику.",
+	model.GameOutcomeAbandoned: "Дебаты прерваны: ни один из участников не дождался их окончания.",
+	model.GameOutcomeNoShow:    "Дебаты не состоялись: второй участник не пришёл.",
+}
+
+// GameRepository хранит идущие игры в live_games, чтобы их видели все экземпляры сервиса.
+// Изменения одной комнаты выполняются по очереди под блокировкой её строки.
 type GameRepository struct {
-	Games   map[string]model.GameStatus
-	Mu      sync.Mutex
 	Results []string
 	db      *pg.DB
+	events  repo.EventBus[model.GameStatus]
+	policy  repo.ResultPolicy
 }
 
-func (g *GameRepository) SetWinnerId(ctx context.Context, roomID string, winnerID int) error {
-	game := model.Game{
-		WinnerID: int64(winnerID),
+// updateGame блокирует игру до конца транзакции, меняет её через change и сохраняет, если change
+// вернул true. О новом состоянии подписчики узнают после коммита.
+func (g *GameRepository) updateGame(
+	ctx context.Context,
+	roomId string,
+	change func(game *model.GameStatus) (bool, error),
+) (model.GameStatus, error) {
+	tx, err := g.db.Begin()
+	if err != nil {
+		return model.GameStatus{}, tracerr.Errorf("failed update game: %w", err)
 	}
+	defer tx.Rollback()
 
-	fmt.Printf("%+v\n", game)
+	game := model.GameStatus{}
+	err = tx.ModelContext(ctx, &game).
+		Where("id = ?", roomId).
+		For("UPDATE").
+		Select()
+	if err != nil {
+		if isNoRowsError(err) {
+			return model.GameStatus{}, repo.ErrNotFound
+		}
+		return model.GameStatus{}, tracerr.Errorf("failed update game: %w", err)
+	}
 
-	_, err := g.db.ModelContext(ctx, &game).Where("room_uid = ?", roomID).Column("winner_id").Update()
+	changed, err := change(&game)
 	if err != nil {
-		return err
+		return model.GameStatus{}, err
+	}
+	if !changed {
+		return game, nil
+	}
+
+	if err = saveGame(ctx, tx, &game); err != nil {
+		return model.GameStatus{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return model.GameStatus{}, tracerr.Errorf("failed update game: %w", err)
+	}
+
+	g.events.Publish(game.ID, game)
+	return game, nil
+}
+
+// saveGame сохраняет состояние игры и, если итог вынесен, записывает его в games в той же транзакции:
+// итог не потеряется между live_games и статистикой.
+func saveGame(ctx context.Context, tx orm.DB, game *model.GameStatus) error {
+	if _, err := tx.ModelContext(ctx, game).WherePK().Update(); err != nil {
+		return tracerr.Errorf("failed save game: %w", err)
+	}
+	if game.Outcome == "" || game.AwaitingJudge {
+		return nil
+	}
+
+	var winner *int
+	if game.WinnerId != 0 {
+		winner = &game.WinnerId
+	}
+
+	_, err := tx.ModelContext(ctx, (*model.Game)(nil)).
+		Set("outcome = ?", game.Outcome).
+		Set("winner_id = ?", winner).
+		Where("room_uid = ?", game.ID).
+		Update()
+	if err != nil {
+		return tracerr.Errorf("failed save game outcome: %w", err)
 	}
 
 	return nil
 }
 
-func (g *GameRepository) IsGameOverByDeadline(ctx context.Context, roomId string) bool {
-	g.Mu.Lock()
-	game, ok := g.Games[roomId]
-	g.Mu.Unlock()
+func (g *GameRepository) GetPlayedGame(ctx context.Context, roomID string) (model.Game, error) {
+	game := model.Game{}
+	err := g.db.ModelContext(ctx, &game).
+		Where("room_uid = ?", roomID).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	if err != nil {
+		if isNoRowsError(err) {
+			return model.Game{}, repo.ErrNotFound
+		}
+		return model.Game{}, tracerr.Errorf("failed get played game: %w", err)
+	}
 
-	if !ok {
-		return true
+	return game, nil
+}
+
+func (g *GameRepository) IsGameOverByDeadline(ctx context.Context, roomId string) bool {
+	game, err := g.GetGameById(ctx, roomId)
+	if err != nil {
+		return errors.Is(err, repo.ErrNotFound)
 	}
 
-	deadline := game.FirstRequest.UTC().Add(waitingDuration)
-	return time.Now().UTC().After(deadline)
+	// Дедлайн ожидания есть только у игры, в которую ещё не пришёл второй игрок
+	return game.GameStatusEnum == model.GameStatusPending && isWaitingOver(game, time.Now().UTC())
 }
 
-func NewGameRepository(db *pg.DB) *GameRepository {
+func NewGameRepository(db *pg.DB, events repo.EventBus[model.GameStatus], policy repo.ResultPolicy) *GameRepository {
 
 	results := []string{
 		"%s победил в дебатах: его аргументы звучали чётче и убедительнее. Второй участник не смог достойно ответить на его доводы.",
@@ -81,139 +163,310 @@ func NewGameRepository(db *pg.DB) *GameRepository {
 	}
 
 	return &GameRepository{
-		Games:   make(map[string]model.GameStatus),
-		Mu:      sync.Mutex{},
 		Results: results,
 		db:      db,
+		events:  events,
+		policy:  policy,
 	}
 }
 
 // FinishGame implements repo.GameRepository.
 func (g *GameRepository) FinishGame(ctx context.Context, finishGame model.FinishGame) (model.GameResult, error) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+	game, err := g.updateGame(ctx, finishGame.RoomID, func(game *model.GameStatus) (bool, error) {
+		if !game.HasPlayer(finishGame.FromUserID) {
+			return false, model.ErrNotGamePlayer
+		}
 
-	game, ok := g.Games[finishGame.RoomID]
-	if !ok {
-		return model.GameResult{}, tracerr.New("Game not found")
-	}
+		// Игру уже завершил второй игрок или фоновая задача по дедлайну
+		if game.GameStatusEnum == model.GameStatusFinished {
+			return false, nil
+		}
+		if game.GameStatusEnum != model.GameStatusStarted {
+			return false, &model.GameTransitionError{From: game.GameStatusEnum, To: model.GameStatusFinished}
+		}
+
+		now := time.Now().UTC()
+		if finishGame.SecondsInGame < 0 || finishGame.SecondsInGame > maxScore(*game, now) {
+			return false, repo.ErrValidation
+		}
+
+		if game.FirstFinishRequest == nil {
+			game.FirstFinishRequest = &now
+		}
+
+		// Повторный отчёт игрока не меняет уже присланный результат, но может завершить игру,
+		// если соперник так и не ответил
+		reported := game.FirstPlayerReported
+		if game.SecondPlayerId == finishGame.FromUserID {
+			reported = game.SecondPlayerReported
+		}
+		if !reported && game.FirstPlayerId == finishGame.FromUserID {
+			game.FirstPlayerScore = finishGame.SecondsInGame
+			game.FirstPlayerReported = true
+		} else if !reported {
+			game.SecondPlayerScore = finishGame.SecondsInGame
+			game.SecondPlayerReported = true
+		}
+
+		gameFinished := game.FirstPlayerReported && game.SecondPlayerReported ||
+			now.After(game.FirstFinishRequest.Add(waitingDuration))
+		if gameFinished {
+			return true, g.finishGame(game, now)
+		}
 
-	if game.FirstFinishRequest == nil {
-		now := time.Now()
-		game.FirstFinishRequest = &now
+		return !reported, nil
+	})
+	if err != nil {
+		return model.GameResult{}, err
 	}
-	if game.FirstPlayerId == finishGame.FromUserID {
-		game.FirstPlayerScore = finishGame.SecondsInGame
-	} else {
-		game.SecondPlayerScore = finishGame.SecondsInGame
+
+	if game.GameStatusEnum != model.GameStatusFinished {
+		return model.GameResult{
+			RoomID:     finishGame.RoomID,
+			WinnerId:   0,
+			ResultText: "",
+		}, nil
 	}
 
-	g.Games[finishGame.RoomID] = game
+	return g.gameResult(game), nil
+}
 
-	gameFinished := game.FirstPlayerScore != 0 &&
-		game.SecondPlayerScore != 0 ||
-		time.Now().UTC().After(game.FirstFinishRequest.UTC().Add(waitingDuration))
+// ExpireGames завершает игры, дедлайн которых прошёл, и возвращает их новое состояние:
+// PENDING без второго игрока отклоняются, STARTED без результатов от обоих игроков завершаются
+// по тем результатам, что успели прийти. Игры, которые сейчас меняет другой запрос или другой
+// экземпляр сервиса, пропускаются до следующего запуска.
+func (g *GameRepository) ExpireGames(ctx context.Context, now time.Time) ([]model.GameStatus, error) {
+	tx, err := g.db.Begin()
+	if err != nil {
+		return nil, tracerr.Errorf("failed expire games: %w", err)
+	}
+	defer tx.Rollback()
+
+	overdue := now.Add(-waitingDuration)
+
+	var games []model.GameStatus
+	err = tx.ModelContext(ctx, &games).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("status = ?", model.GameStatusPending).
+				Where("first_request < ?", overdue), nil
+		}).
+		WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("status = ?", model.GameStatusStarted).
+				WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+					return q.Where("finish_at < ?", overdue).
+						WhereOr("first_finish_request < ?", overdue), nil
+				}), nil
+		}).
+		For("UPDATE SKIP LOCKED").
+		Select()
+	if err != nil {
+		return nil, tracerr.Errorf("failed expire games: %w", err)
+	}
 
-	if gameFinished {
-		var winnerID int
-		delta := game.FirstPlayerScore - game.SecondPlayerScore
-		if delta > int(gameDuration/10) {
-			winnerID = game.FirstPlayerId
-		} else if delta < -int(gameDuration/10) {
-			winnerID = game.SecondPlayerId
-		} else if game.FirstFinishRequest.Second()%2 == 0 {
-			winnerID = game.FirstPlayerId
-		} else {
-			winnerID = game.SecondPlayerId
+	var expired []model.GameStatus
+	for _, game := range games {
+		switch {
+		case game.GameStatusEnum == model.GameStatusPending && isWaitingOver(game, now):
+			err = declineGame(&game, now)
+		case game.GameStatusEnum == model.GameStatusStarted && isFinishOverdue(game, now):
+			err = g.finishGame(&game, now)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
 		}
-		g.Games[finishGame.RoomID] = game
-
-		var chislo rune = 0x00
 
-		for _, v := range game.ID {
-			chislo = chislo ^ v
+		if err = saveGame(ctx, tx, &game); err != nil {
+			return nil, err
 		}
+		expired = append(expired, game)
+	}
 
-		return model.GameResult{
-			RoomID:     game.ID,
-			WinnerId:   winnerID,
-			ResultText: g.Results[int(chislo)%len(g.Results)],
-		}, nil
+	if err = tx.Commit(); err != nil {
+		return nil, tracerr.Errorf("failed expire games: %w", err)
+	}
+
+	for _, game := range expired {
+		g.events.Publish(game.ID, game)
+	}
+
+	return expired, nil
+}
+
+// EvictGames забывает отклонённые и завершённые игры, закончившиеся раньше before. Игры, которые ждут
+// судью, остаются, пока он не вынесет итог.
+func (g *GameRepository) EvictGames(ctx context.Context, before time.Time) (int, error) {
+	result, err := g.db.ModelContext(ctx, (*model.GameStatus)(nil)).
+		Where("status IN (?)", pg.In([]model.GameStatusEnum{model.GameStatusDeclined, model.GameStatusFinished})).
+		Where("finish_at < ?", before).
+		Where("NOT awaiting_judge").
+		Delete()
+	if err != nil {
+		return 0, tracerr.Errorf("failed evict games: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// finishGame подводит итог начатой игры. Вызывается под блокировкой строки игры.
+func (g *GameRepository) finishGame(game *model.GameStatus, now time.Time) error {
+	if err := game.Transition(model.GameStatusFinished, now); err != nil {
+		return err
+	}
+
+	switch {
+	case game.FirstPlayerReported && game.SecondPlayerReported:
+		game.ApplyVerdict(g.policy.Decide(*game))
+	case game.FirstPlayerReported:
+		game.ApplyVerdict(model.GameVerdict{WinnerId: game.FirstPlayerId, Outcome: model.GameOutcomeForfeit})
+	case game.SecondPlayerReported:
+		game.ApplyVerdict(model.GameVerdict{WinnerId: game.SecondPlayerId, Outcome: model.GameOutcomeForfeit})
+	default:
+		game.ApplyVerdict(model.GameVerdict{Outcome: model.GameOutcomeAbandoned})
+	}
+	game.FinishAt = now
+	return nil
+}
+
+// declineGame отклоняет игру, в которую второй игрок не пришёл вовремя. Вызывается под блокировкой
+// строки игры.
+func declineGame(game *model.GameStatus, now time.Time) error {
+	if err := game.Transition(model.GameStatusDeclined, now); err != nil {
+		return err
+	}
+
+	game.ApplyVerdict(model.GameVerdict{Outcome: model.GameOutcomeNoShow})
+	game.FinishAt = now
+	return nil
+}
+
+func isWaitingOver(game model.GameStatus, now time.Time) bool {
+	return now.After(game.FirstRequest.Add(waitingDuration))
+}
+
+// isFinishOverdue — время игры и ожидания результатов вышло, а оба результата так и не пришли.
+func isFinishOverdue(game model.GameStatus, now time.Time) bool {
+	if game.FirstFinishRequest != nil && now.After(game.FirstFinishRequest.Add(waitingDuration)) {
+		return true
 	}
 
-	return model.GameResult{
-		RoomID:     finishGame.RoomID,
-		WinnerId:   0,
-		ResultText: "",
-	}, nil
+	return now.After(game.FinishAt.Add(waitingDuration))
 }
 
-func (g *GameRepository) FinishGameByDeadline(ctx context.Context, fromUserId int, currentGameStatus model.GameStatus) (model.GameStatus, error) {
-	if fromUserId == currentGameStatus.FirstPlayerId {
-		currentGameStatus.WinnerId = currentGameStatus.SecondPlayerId
+// maxScore — сколько секунд игрок мог провести в игре по часам сервера.
+func maxScore(game model.GameStatus, now time.Time) int {
+	elapsed := now.Sub(game.StartAt)
+	if elapsed > gameDuration {
+		elapsed = gameDuration
+	}
+
+	return int((elapsed + scoreTolerance) / time.Second)
+}
+
+func (g *GameRepository) gameResult(game model.GameStatus) model.GameResult {
+	var chislo rune = 0x00
+
+	for _, v := range game.ID {
+		chislo = chislo ^ v
+	}
+
+	result := model.GameResult{
+		RoomID:        game.ID,
+		WinnerId:      game.WinnerId,
+		Outcome:       game.Outcome,
+		AwaitingJudge: game.AwaitingJudge,
+	}
+	if game.Outcome == model.GameOutcomeWin {
+		result.ResultText = g.Results[int(chislo)%len(g.Results)]
 	} else {
-		currentGameStatus.WinnerId = currentGameStatus.FirstPlayerId
+		result.ResultText = outcomeResultTexts[game.Outcome]
 	}
 
-	currentGameStatus.FinishAt = time.Now().UTC()
-	currentGameStatus.GameStatusEnum = model.GameStatusDeclined
+	return result
+}
+
+func (g *GameRepository) JudgeGame(ctx context.Context, roomId string, verdict model.GameVerdict) (model.GameStatus, error) {
+	return g.updateGame(ctx, roomId, func(game *model.GameStatus) (bool, error) {
+		if game.GameStatusEnum != model.GameStatusFinished || !game.AwaitingJudge {
+			return false, model.ErrNotAwaitingJudge
+		}
+		if !verdict.Decided() || verdict.WinnerId != 0 && !game.HasPlayer(verdict.WinnerId) {
+			return false, repo.ErrValidation
+		}
+
+		game.ApplyVerdict(verdict)
+		return true, nil
+	})
+}
+
+func (g *GameRepository) FinishGameByDeadline(ctx context.Context, roomId string) (model.GameStatus, error) {
+	return g.updateGame(ctx, roomId, func(game *model.GameStatus) (bool, error) {
+		// Пока ждали блокировку, игру мог отклонить другой запрос или начать второй игрок
+		now := time.Now().UTC()
+		if game.GameStatusEnum != model.GameStatusPending || !isWaitingOver(*game, now) {
+			return false, nil
+		}
 
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-	g.Games[currentGameStatus.ID] = currentGameStatus
-	return g.Games[currentGameStatus.ID], nil
+		return true, declineGame(game, now)
+	})
 }
 
 func (g *GameRepository) GetGameById(ctx context.Context, roomId string) (model.GameStatus, error) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-	return g.Games[roomId], nil
+	game := model.GameStatus{}
+	err := g.db.ModelContext(ctx, &game).
+		Where("id = ?", roomId).
+		Select()
+	if err != nil {
+		if isNoRowsError(err) {
+			return model.GameStatus{}, repo.ErrNotFound
+		}
+		return model.GameStatus{}, tracerr.Errorf("failed get game: %w", err)
+	}
+
+	return game, nil
 }
 
 func (g *GameRepository) StartGame(ctx context.Context, startGame model.StartGame) (model.GameStatus, error) {
-	g.Mu.Lock()
-	game, exist := g.Games[startGame.RoomID]
-	g.Mu.Unlock()
-
-	if !exist {
-		// Пришёл первый игрок
-		newGame := model.GameStatus{
-			ID:             startGame.RoomID,
-			FirstPlayerId:  startGame.FromUserID,
-			FirstRequest:   time.Now().UTC(),
-			GameStatusEnum: model.GameStatusPending,
-			StartAt:        time.Now().UTC(),
-			FinishAt:       time.Now().UTC(),
-		}
-		g.Mu.Lock()
-		g.Games[newGame.ID] = newGame
-		g.Mu.Unlock()
-	} else {
-		// Ретрай от первого игрока
-		if game.FirstPlayerId == startGame.FromUserID {
-			return game, nil
+	// Пришёл первый игрок. Если комната уже есть, вставка ничего не делает, и решение принимается
+	// под блокировкой её строки
+	game := model.NewPendingGame(startGame.RoomID, startGame.FromUserID, time.Now().UTC())
+	_, err := g.db.ModelContext(ctx, &game).
+		OnConflict("(id) DO NOTHING").
+		Insert()
+	if err == nil {
+		g.events.Publish(game.ID, game)
+		return game, nil
+	}
+	// Пустой RETURNING означает, что комната уже есть
+	if !isNoRowsError(err) {
+		return model.GameStatus{}, tracerr.Errorf("failed start game: %w", err)
+	}
+
+	return g.updateGame(ctx, startGame.RoomID, func(game *model.GameStatus) (bool, error) {
+		// Ретрай от уже пришедшего игрока
+		if game.HasPlayer(startGame.FromUserID) {
+			return false, nil
+		}
+		if game.SecondPlayerId != 0 {
+			return false, model.ErrGameFull
 		}
+
+		now := time.Now().UTC()
+
 		// Завершение игры по дедлайну ожидания подтверждения начала игры от второго игрока
-		if g.IsGameOverByDeadline(ctx, game.ID) {
-			return g.FinishGameByDeadline(ctx, startGame.FromUserID, game)
+		if game.GameStatusEnum == model.GameStatusPending && isWaitingOver(*game, now) {
+			return true, declineGame(game, now)
 		}
 
 		// Пришёл второй игрок
-		newGame := model.GameStatus{
-			ID:             game.ID,
-			FirstPlayerId:  game.FirstPlayerId,
-			SecondPlayerId: startGame.FromUserID,
-			FirstRequest:   time.Now().UTC(),
-			GameStatusEnum: model.GameStatusStarted,
-			StartAt:        time.Now().UTC(),
-			FinishAt:       time.Now().Add(gameDuration).UTC(),
-		}
-		g.Mu.Lock()
-		g.Games[newGame.ID] = newGame
-		g.Mu.Unlock()
-	}
-
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-	return g.Games[startGame.RoomID], nil
+		if err := game.Transition(model.GameStatusStarted, now); err != nil {
+			return false, err
+		}
+		game.SecondPlayerId = startGame.FromUserID
+		game.FirstRequest = now
+		game.StartAt = now
+		game.FinishAt = now.Add(gameDuration)
+		return true, nil
+	})
 }
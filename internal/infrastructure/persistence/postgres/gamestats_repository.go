@@ -58,12 +58,14 @@ func (u *GameStatsRepository) getTotalUserGameStats(ctx context.Context, userId
 
 		var totalStats model.UserGameStats
 		_, err := u.db.QueryOneContext(ctx, &totalStats, `
-		SELECT ? AS user_id,
+		SELECT ?0 AS user_id,
 		       COUNT(*) AS games_amount,
-		       COUNT(*) FILTER (WHERE winner_id = ?) AS wins_amount
+		       COUNT(*) FILTER (WHERE winner_id = ?0) AS wins_amount,
+		       COUNT(*) FILTER (WHERE outcome = 'DRAW') AS draws_amount,
+		       COUNT(*) FILTER (WHERE outcome = 'FORFEIT' AND winner_id <> ?0) AS forfeits_amount
 		FROM games
-		WHERE first_player_id = ? OR second_player_id = ?
-	`, userId, userId, userId, userId)
+		WHERE first_player_id = ?0 OR second_player_id = ?0
+	`, userId)
 
 		if err != nil {
 			res <- UserGameStatsResult{
@@ -92,20 +94,24 @@ func (u *GameStatsRepository) getUserMetaTopicStats(ctx context.Context, userId
 		defer close(res)
 
 		var metaTopicStats []struct {
-			MetaTopicName string
-			GamesAmount   int
-			WinsAmount    int
+			MetaTopicName  string
+			GamesAmount    int
+			WinsAmount     int
+			DrawsAmount    int
+			ForfeitsAmount int
 		}
 
 		_, err := u.db.QueryContext(ctx, &metaTopicStats, `
 			SELECT m.name AS meta_topic_name,
 			       COUNT(*) AS games_amount,
-			       COUNT(*) FILTER (WHERE winner_id = ?) AS wins_amount
+			       COUNT(*) FILTER (WHERE winner_id = ?0) AS wins_amount,
+			       COUNT(*) FILTER (WHERE g.outcome = 'DRAW') AS draws_amount,
+			       COUNT(*) FILTER (WHERE g.outcome = 'FORFEIT' AND winner_id <> ?0) AS forfeits_amount
 			FROM games g
 			JOIN metatopics m ON g.metatopic_id = m.id
-			WHERE (g.first_player_id = ? OR g.second_player_id = ?)
+			WHERE (g.first_player_id = ?0 OR g.second_player_id = ?0)
 			GROUP BY m.name
-		`, userId, userId, userId)
+		`, userId)
 
 		if err != nil {
 			res <- UserMetaTopicStatsResult{
@@ -117,9 +123,11 @@ func (u *GameStatsRepository) getUserMetaTopicStats(ctx context.Context, userId
 		metaTopicMap := make(map[string]model.UserGameStats)
 		for _, stat := range metaTopicStats {
 			metaTopicMap[stat.MetaTopicName] = model.UserGameStats{
-				UserId:      userId,
-				GamesAmount: stat.GamesAmount,
-				WinsAmount:  stat.WinsAmount,
+				UserId:         userId,
+				GamesAmount:    stat.GamesAmount,
+				WinsAmount:     stat.WinsAmount,
+				DrawsAmount:    stat.DrawsAmount,
+				ForfeitsAmount: stat.ForfeitsAmount,
 			}
 		}
 
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.NotificationPreferenceRepository = (*NotificationPreferenceRepository)(nil)
+)
+
+type NotificationPreferenceRepository struct {
+	db *pg.DB
+}
+
+func NewNotificationPreferenceRepository(db *pg.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		db: db,
+	}
+}
+
+func (n *NotificationPreferenceRepository) GetNotificationPreferences(ctx context.Context, userId int) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	err := n.db.ModelContext(ctx, &preferences).
+		Where("user_id = ?", userId).
+		Select()
+	if err != nil {
+		return nil, tracerr.Errorf("failed get notification preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+func (n *NotificationPreferenceRepository) SetNotificationPreferences(ctx context.Context, preferences []model.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for i := range preferences {
+		preferences[i].UpdatedAt = now
+	}
+
+	_, err := n.db.ModelContext(ctx, &preferences).
+		OnConflict("(user_id, category) DO UPDATE").
+		Set("enabled = EXCLUDED.enabled").
+		Set("updated_at = EXCLUDED.updated_at").
+		Insert()
+	if err != nil {
+		if getConstraint(err) != "" {
+			return repo.ErrNotFound
+		}
+		return tracerr.Errorf("failed set notification preferences: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.NotificationRepository = (*NotificationRepository)(nil)
+)
+
+type NotificationRepository struct {
+	db *pg.DB
+}
+
+func NewNotificationRepository(db *pg.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+func (n *NotificationRepository) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	notification.CreatedAt = time.Now().UTC()
+	_, err := n.db.ModelContext(ctx, notification).
+		OnConflict("(user_id, dedup_key) DO NOTHING").
+		Insert()
+	if err != nil {
+		return tracerr.Errorf("failed create notification: %w", err)
+	}
+
+	return nil
+}
+
+func (n *NotificationRepository) GetNotifications(
+	ctx context.Context,
+	userId int,
+	afterId *int,
+	limit int,
+	unreadOnly bool,
+) ([]model.Notification, error) {
+	var notifications []model.Notification
+	q := n.db.ModelContext(ctx, &notifications).
+		Where("user_id = ?", userId).
+		Order("id DESC").
+		Limit(limit)
+	if afterId != nil {
+		q = q.Where("id < ?", *afterId)
+	}
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, tracerr.Errorf("failed get notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (n *NotificationRepository) CountUnreadNotifications(ctx context.Context, userId int) (int, error) {
+	count, err := n.db.ModelContext(ctx, &model.Notification{}).
+		Where("user_id = ?", userId).
+		Where("read_at IS NULL").
+		Count()
+	if err != nil {
+		return 0, tracerr.Errorf("failed count notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (n *NotificationRepository) MarkNotificationsRead(ctx context.Context, userId int, ids []int) error {
+	q := n.db.ModelContext(ctx, &model.Notification{}).
+		Set("read_at = now()").
+		Where("user_id = ?", userId).
+		Where("read_at IS NULL")
+	if len(ids) != 0 {
+		q = q.Where("id IN (?)", pg.In(ids))
+	}
+
+	if _, err := q.Update(); err != nil {
+		return tracerr.Errorf("failed mark notifications read: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/pubsub"
+	"github.com/go-pg/pg/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	_ repo.EventBus[struct{}] = (*NotifyBus[struct{}])(nil)
+)
+
+// notifyEnvelope — событие в payload NOTIFY. Postgres ограничивает payload 8000 байт.
+type notifyEnvelope[T any] struct {
+	Topic string `json:"topic"`
+	Event T      `json:"event"`
+}
+
+// NotifyBus раздаёт события подписчикам всех экземпляров сервиса: Publish отправляет событие
+// в канал Postgres, а Listen каждого экземпляра передаёт пришедшие события своим подписчикам.
+type NotifyBus[T any] struct {
+	db      *pg.DB
+	channel string
+	local   *pubsub.Broker[T]
+	logger  *zap.Logger
+}
+
+func NewNotifyBus[T any](db *pg.DB, channel string, buffer int, logger *zap.Logger) *NotifyBus[T] {
+	return &NotifyBus[T]{
+		db:      db,
+		channel: channel,
+		local:   pubsub.NewBroker[T](buffer),
+		logger:  logger,
+	}
+}
+
+// Publish отправляет событие через NOTIFY. Если Postgres недоступен, событие получат хотя бы
+// подписчики этого экземпляра.
+func (b *NotifyBus[T]) Publish(topic string, event T) {
+	payload, err := json.Marshal(notifyEnvelope[T]{Topic: topic, Event: event})
+	if err == nil {
+		_, err = b.db.Exec("SELECT pg_notify(?, ?)", b.channel, string(payload))
+	}
+	if err != nil {
+		b.logger.Error("failed to notify event",
+			zap.String("channel", b.channel),
+			zap.String("topic", topic),
+			zap.Error(err),
+		)
+		b.local.Publish(topic, event)
+	}
+}
+
+func (b *NotifyBus[T]) Subscribe(ctx context.Context, topic string) <-chan T {
+	return b.local.Subscribe(ctx, topic)
+}
+
+// Listen слушает канал, пока не отменят ctx. Разорванное соединение Listener восстанавливает сам.
+func (b *NotifyBus[T]) Listen(ctx context.Context) {
+	ln := b.db.Listen(b.channel)
+	defer ln.Close()
+
+	notifications := ln.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+
+			var envelope notifyEnvelope[T]
+			if err := json.Unmarshal([]byte(notification.Payload), &envelope); err != nil {
+				b.logger.Error("failed to decode event", zap.String("channel", b.channel), zap.Error(err))
+				continue
+			}
+			b.local.Publish(envelope.Topic, envelope.Event)
+		}
+	}
+}
@@ -25,17 +25,30 @@ func NewRecoveryCodeRepository(
 	}
 }
 
-func (c *RecoveryCodeRepository) CreateRecoveryCode(ctx context.Context, code *model.RecoveryCode) (*model.RecoveryCode, error) {
-	_, err := c.db.ModelContext(ctx, code).
+func (c *RecoveryCodeRepository) CreateRecoveryCode(ctx context.Context, code *model.RecoveryCode, email *model.OutboxEmail) (*model.RecoveryCode, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return nil, tracerr.Errorf("failed insert user code: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ModelContext(ctx, code).
 		OnConflict("(email) DO UPDATE").
 		Set("code = ?", code.Code).
 		Set("expired_at = ?", code.ExpiredAt).
 		Insert()
-
 	if err != nil {
 		return nil, tracerr.Errorf("failed insert user code: %w", err)
 	}
 
+	if err = enqueueEmail(ctx, tx, email); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, tracerr.Errorf("failed insert user code: %w", err)
+	}
+
 	return code, nil
 }
 
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/go-pg/pg/v9"
+	"github.com/go-pg/pg/v9/orm"
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	_ repo.ReportRepository = (*ReportRepository)(nil)
+)
+
+type ReportRepository struct {
+	db *pg.DB
+}
+
+func NewReportRepository(db *pg.DB) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+func (r *ReportRepository) CreateReport(ctx context.Context, report *model.Report) (*model.Report, error) {
+	_, err := r.db.ModelContext(ctx, report).Insert()
+	if err != nil {
+		if getConstraint(err) == "unique_reports_room" {
+			return nil, repo.ErrAlreadyExist
+		}
+		if getConstraint(err) != "" {
+			return nil, repo.ErrValidation
+		}
+		return nil, tracerr.Errorf("failed create report: %w", err)
+	}
+
+	return report, nil
+}
+
+func (r *ReportRepository) GetReport(ctx context.Context, reportId int) (*model.ReportDetails, error) {
+	report, err := r.selectReport(ctx, r.db, reportId)
+	if err != nil {
+		return nil, tracerr.Wrap(err)
+	}
+
+	details, err := r.details(ctx, r.db, []model.Report{*report})
+	if err != nil {
+		return nil, err
+	}
+
+	return &details[0], nil
+}
+
+func (r *ReportRepository) GetReports(
+	ctx context.Context,
+	statuses []model.ReportStatusEnum,
+	reportedUserId *int,
+	pageSize, pageNumber int,
+) ([]model.ReportDetails, int, error) {
+	filter := func(q *orm.Query) (*orm.Query, error) {
+		q = q.WhereIn("status IN (?)", statuses)
+		if reportedUserId != nil {
+			q = q.Where("reported_user_id = ?", *reportedUserId)
+		}
+		return q, nil
+	}
+
+	rows, err := r.db.ModelContext(ctx, &model.Report{}).
+		Apply(filter).
+		Count()
+	if err != nil {
+		return nil, 0, tracerr.Errorf("failed get reports: %w", err)
+	}
+
+	var reports []model.Report
+	err = r.db.ModelContext(ctx, &reports).
+		Apply(filter).
+		Order("created_at ASC").
+		Offset(pageNumber * pageSize).
+		Limit(pageSize).
+		Select()
+	if err != nil {
+		return nil, 0, tracerr.Errorf("failed get reports: %w", err)
+	}
+
+	result, err := r.details(ctx, r.db, reports)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, rows, nil
+}
+
+func (r *ReportRepository) ResolveReport(ctx context.Context, reportId int, action *model.ModerationAction) (*model.ReportDetails, error) {
+	var details []model.ReportDetails
+	err := r.db.WithContext(ctx).RunInTransaction(func(tx *pg.Tx) error {
+		report := &model.Report{}
+		err := tx.ModelContext(ctx, report).
+			Where("id = ?", reportId).
+			For("UPDATE").
+			Select()
+		if err != nil {
+			if isNoRowsError(err) {
+				return repo.ErrNotFound
+			}
+			return tracerr.Errorf("failed resolve report: %w", err)
+		}
+
+		if report.Status != model.ReportStatusOpen {
+			return repo.ErrValidation
+		}
+
+		action.ReportID = &report.ID
+		action.UserID = report.ReportedUserID
+		if _, err = tx.ModelContext(ctx, action).Insert(); err != nil {
+			return tracerr.Errorf("failed insert moderation action: %w", err)
+		}
+
+		now := time.Now().UTC()
+		report.Status = model.ReportStatusResolved
+		report.Resolution = &action.Action
+		report.ResolvedAt = &now
+		_, err = tx.ModelContext(ctx, report).
+			Column("status", "resolution", "resolved_at").
+			WherePK().
+			Update()
+		if err != nil {
+			return tracerr.Errorf("failed resolve report: %w", err)
+		}
+
+		if action.Action == model.ModerationActionBan {
+			_, err = tx.ModelContext(ctx, &model.User{}).
+				Set("banned_at = ?", now).
+				Where("id = ?", report.ReportedUserID).
+				Where("banned_at IS NULL").
+				Update()
+			if err != nil {
+				return tracerr.Errorf("failed ban user: %w", err)
+			}
+		}
+
+		details, err = r.details(ctx, tx, []model.Report{*report})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &details[0], nil
+}
+
+func (r *ReportRepository) GetModerationActions(ctx context.Context, userId int, limit, offset int) ([]model.ModerationAction, error) {
+	var actions []model.ModerationAction
+	err := r.db.ModelContext(ctx, &actions).
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Select()
+	if err != nil {
+		return nil, tracerr.Errorf("failed get moderation actions: %w", err)
+	}
+
+	return actions, nil
+}
+
+func (r *ReportRepository) GetOffenderStats(ctx context.Context, userId int) (*model.OffenderStats, error) {
+	stats, err := r.offenderStats(ctx, r.db, []int{userId})
+	if err != nil {
+		return nil, err
+	}
+
+	result := stats[userId]
+	return &result, nil
+}
+
+func (r *ReportRepository) selectReport(ctx context.Context, db orm.DB, reportId int) (*model.Report, error) {
+	report := &model.Report{}
+	err := db.ModelContext(ctx, report).
+		Where("id = ?", reportId).
+		Select()
+	if err != nil {
+		if isNoRowsError(err) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Errorf("failed get report: %w", err)
+	}
+
+	return report, nil
+}
+
+// details дополняет жалобы действиями модераторов и статистикой нарушителей: по одному запросу
+// на каждое, сколько бы жалоб ни было.
+func (r *ReportRepository) details(ctx context.Context, db orm.DB, reports []model.Report) ([]model.ReportDetails, error) {
+	if len(reports) == 0 {
+		return []model.ReportDetails{}, nil
+	}
+
+	reportIds := make([]int, 0, len(reports))
+	userIds := make([]int, 0, len(reports))
+	for _, report := range reports {
+		reportIds = append(reportIds, report.ID)
+		userIds = append(userIds, report.ReportedUserID)
+	}
+
+	var actions []model.ModerationAction
+	err := db.ModelContext(ctx, &actions).
+		Where("report_id IN (?)", pg.In(reportIds)).
+		Order("created_at ASC").
+		Select()
+	if err != nil {
+		return nil, tracerr.Errorf("failed get report actions: %w", err)
+	}
+
+	actionsByReport := make(map[int][]model.ModerationAction, len(reports))
+	for _, action := range actions {
+		actionsByReport[*action.ReportID] = append(actionsByReport[*action.ReportID], action)
+	}
+
+	stats, err := r.offenderStats(ctx, db, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ReportDetails, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, model.ReportDetails{
+			Report:        report,
+			Actions:       actionsByReport[report.ID],
+			OffenderStats: stats[report.ReportedUserID],
+		})
+	}
+
+	return result, nil
+}
+
+// offenderStats — статистика нарушений по каждому из userIds. У пользователя без жалоб и
+// действий модераторов она нулевая.
+func (r *ReportRepository) offenderStats(ctx context.Context, db orm.DB, userIds []int) (map[int]model.OffenderStats, error) {
+	var rows []model.OffenderStats
+	_, err := db.QueryContext(ctx, &rows, `
+		SELECT u.id AS user_id,
+		       (SELECT COUNT(*) FROM reports r WHERE r.reported_user_id = u.id) AS reports_count,
+		       (SELECT COUNT(*) FROM moderation_actions ma WHERE ma.user_id = u.id AND ma.action = 'WARN') AS warnings_count,
+		       (SELECT COUNT(*) FROM moderation_actions ma WHERE ma.user_id = u.id AND ma.action = 'BAN') AS bans_count
+		FROM users u
+		WHERE u.id IN (?)
+	`, pg.In(userIds))
+	if err != nil {
+		return nil, tracerr.Errorf("failed get offender stats: %w", err)
+	}
+
+	stats := make(map[int]model.OffenderStats, len(userIds))
+	for _, userId := range userIds {
+		stats[userId] = model.OffenderStats{UserID: userId}
+	}
+	for _, row := range rows {
+		stats[row.UserID] = row
+	}
+
+	return stats, nil
+}
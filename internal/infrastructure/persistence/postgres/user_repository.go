@@ -2,7 +2,6 @@ package postgres
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
@@ -42,7 +41,9 @@ func (u *UserRepository) CreateUser(ctx context.Context, user *model.User) (*mod
 }
 
 func (u *UserRepository) UpdateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	// image_id меняется только через setUserImage, иначе разъедутся счётчики ссылок
 	_, err := u.db.ModelContext(ctx, user).
+		ExcludeColumn("image_id").
 		Where("id in (?)", user.ID).
 		Update()
 
@@ -57,6 +58,21 @@ func (u *UserRepository) UpdateUser(ctx context.Context, user *model.User) (*mod
 	return user, nil
 }
 
+func (u *UserRepository) UpdateUserPassword(ctx context.Context, userId int, password string) error {
+	result, err := u.db.ModelContext(ctx, (*model.User)(nil)).
+		Set("password = ?", password).
+		Where("id = ?", userId).
+		Update()
+	if err != nil {
+		return tracerr.Errorf("failed update user password: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
 func (u *UserRepository) FindUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	result := &model.User{}
 	q := u.db.ModelContext(ctx, result).
@@ -109,55 +125,299 @@ func (u *UserRepository) GetUsers(ctx context.Context, limit int, offset int) ([
 	return users, nil
 }
 
+// AttachImageByHash привязывает к пользователю уже сохранённое изображение с тем же хешем.
+// Возвращает false, если такого изображения нет и его нужно загрузить.
+func (u *UserRepository) AttachImageByHash(ctx context.Context, userId int, hash []byte) (bool, error) {
+	var attached bool
+	err := u.db.WithContext(ctx).RunInTransaction(func(tx *pg.Tx) error {
+		image := model.Image{}
+		err := tx.ModelContext(ctx, &image).
+			Column("id").
+			Where("hash = ?", hash).
+			For("SHARE").
+			Select()
+		if err != nil {
+			if isNoRowsError(err) {
+				return nil
+			}
+			return tracerr.Wrap(err)
+		}
+
+		attached = true
+		return setUserImage(ctx, tx, userId, image.ID)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return attached, nil
+}
+
+func (u *UserRepository) SetUserImage(ctx context.Context, userId int, imageId int) error {
+	return u.db.WithContext(ctx).RunInTransaction(func(tx *pg.Tx) error {
+		// Выбрать можно только изображение, которое пользователь уже загружал или ставил себе
+		if imageId != 0 {
+			exists, err := tx.ModelContext(ctx, &model.Image{}).
+				Join("JOIN user_images AS ui ON ui.image_id = image.id").
+				Where("image.id = ?", imageId).
+				Where("ui.user_id = ?", userId).
+				For("SHARE OF image").
+				Exists()
+			if err != nil {
+				return tracerr.Wrap(err)
+			}
+			if !exists {
+				return repo.ErrNotFound
+			}
+		}
+
+		return setUserImage(ctx, tx, userId, imageId)
+	})
+}
+
+// UploadImage сохраняет новое изображение с вариантами и привязывает его к пользователю.
+// Если параллельная загрузка уже сохранила изображение с тем же хешем, используется оно,
+// а переданные варианты не сохраняются — тогда возвращается false.
 func (u *UserRepository) UploadImage(
 	ctx context.Context,
 	userId int,
-	image, hash []byte,
-	contentType string,
-) error {
-	newImage := model.Image{
-		Hash:        hash,
-		ContentType: contentType,
-		File:        image,
-		CreatedAt:   time.Time{},
-		UpdatedAt:   time.Time{},
-	}
-
-	tx, err := u.db.Begin()
+	image *model.Image,
+	variants []*model.ImageVariant,
+) (bool, error) {
+	var created bool
+	err := u.db.WithContext(ctx).RunInTransaction(func(tx *pg.Tx) error {
+		// Пустой RETURNING означает, что изображение с тем же хешем уже есть
+		_, err := tx.ModelContext(ctx, image).
+			OnConflict("(hash) DO NOTHING").
+			Returning("id").
+			Insert()
+		if err != nil && !isNoRowsError(err) {
+			return tracerr.Wrap(err)
+		}
+
+		created = err == nil
+		if !created {
+			err = tx.ModelContext(ctx, image).
+				Column("id").
+				Where("hash = ?", image.Hash).
+				For("SHARE").
+				Select()
+			if err != nil {
+				return tracerr.Wrap(err)
+			}
+		} else if len(variants) > 0 {
+			for _, variant := range variants {
+				variant.ImageID = image.ID
+			}
+			_, err = tx.ModelContext(ctx, &variants).Insert()
+			if err != nil {
+				return tracerr.Wrap(err)
+			}
+		}
+
+		return setUserImage(ctx, tx, userId, image.ID)
+	})
 	if err != nil {
-		tx.Rollback()
+		return false, err
+	}
+
+	return created, nil
+}
+
+// setUserImage меняет изображение пользователя и пересчитывает ссылки на старое и новое изображения.
+// imageId = 0 отвязывает изображение.
+func setUserImage(ctx context.Context, tx *pg.Tx, userId int, imageId int) error {
+	var oldImageId int
+	_, err := tx.QueryOneContext(ctx, pg.Scan(&oldImageId),
+		`SELECT image_id FROM users WHERE id = ? FOR UPDATE`, userId)
+	if err != nil {
+		if isNoRowsError(err) {
+			return repo.ErrNotFound
+		}
 		return tracerr.Wrap(err)
 	}
+	if oldImageId == imageId {
+		return nil
+	}
 
-	_, err = tx.ModelContext(ctx, &newImage).Insert()
+	_, err = tx.ExecContext(ctx, `UPDATE users SET image_id = NULLIF(?, 0), updated_at = now() WHERE id = ?`, imageId, userId)
 	if err != nil {
-		tx.Rollback()
 		return tracerr.Wrap(err)
 	}
 
-	_, err = tx.ModelContext(ctx, &model.User{}).
-		Set("image_id = ?", newImage.ID).
-		Where("id = ?", userId).
-		Update()
-	fmt.Println(err)
+	if imageId != 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE images SET ref_count = ref_count + 1 WHERE id = ?`, imageId)
+		if err != nil {
+			return tracerr.Wrap(err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO user_images (user_id, image_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, userId, imageId)
+		if err != nil {
+			return tracerr.Wrap(err)
+		}
+	}
+
+	if oldImageId != 0 {
+		// updated_at отсчитывает время жизни изображения без ссылок до сборки мусора
+		_, err = tx.ExecContext(ctx,
+			`UPDATE images SET ref_count = ref_count - 1, updated_at = now() WHERE id = ?`, oldImageId)
+		if err != nil {
+			return tracerr.Wrap(err)
+		}
+	}
+
+	return nil
+}
 
+// DeleteUnreferencedImages удаляет изображения, на которые никто не ссылается с момента before,
+// и в том же запросе ставит их файлы в очередь на удаление из хранилища блобов. Возвращает число
+// удалённых изображений.
+func (u *UserRepository) DeleteUnreferencedImages(ctx context.Context, before time.Time) (int, error) {
+	var deleted int
+	_, err := u.db.QueryOneContext(ctx, pg.Scan(&deleted), `
+		WITH deleted AS (
+			DELETE FROM images i
+			WHERE i.ref_count <= 0
+			  AND i.updated_at < ?
+			  AND NOT EXISTS (SELECT 1 FROM users u WHERE u.image_id = i.id)
+			RETURNING i.id, i.storage_key
+		), queued AS (
+			INSERT INTO blob_deletions (storage_key)
+			SELECT d.storage_key FROM deleted d WHERE d.storage_key <> ''
+			UNION
+			SELECT v.storage_key FROM image_variants v JOIN deleted d ON d.id = v.image_id WHERE v.storage_key <> ''
+			ON CONFLICT (storage_key) DO NOTHING
+		)
+		SELECT count(*) FROM deleted`, before)
 	if err != nil {
-		tx.Rollback()
-		return tracerr.Wrap(err)
+		return 0, tracerr.Errorf("failed delete unreferenced images: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (u *UserRepository) GetBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	var keys []string
+	_, err := u.db.QueryContext(ctx, &keys, `
+		SELECT storage_key FROM blob_deletions
+		ORDER BY attempts, created_at
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, tracerr.Errorf("failed get blob deletions: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (u *UserRepository) CompleteBlobDeletion(ctx context.Context, storageKey string) error {
+	if _, err := u.db.ExecContext(ctx, `DELETE FROM blob_deletions WHERE storage_key = ?`, storageKey); err != nil {
+		return tracerr.Errorf("failed complete blob deletion: %w", err)
 	}
 
-	tx.Commit()
 	return nil
 }
-func (u *UserRepository) DownloadImage(ctx context.Context, userId int) ([]byte, string, error) {
-	user := model.User{}
 
-	err := u.db.ModelContext(ctx, &user).
-		Relation("Image").
-		Where("\"user\".\"id\" = ?", userId).
-		Select()
+func (u *UserRepository) FailBlobDeletion(ctx context.Context, storageKey string, lastError string) error {
+	_, err := u.db.ExecContext(ctx, `
+		UPDATE blob_deletions SET attempts = attempts + 1, last_error = ?
+		WHERE storage_key = ?`, lastError, storageKey)
 	if err != nil {
-		return nil, "", tracerr.Wrap(err)
+		return tracerr.Errorf("failed mark blob deletion failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetInlineImageFiles возвращает файлы изображений и вариантов, ещё не перенесённые в хранилище блобов.
+func (u *UserRepository) GetInlineImageFiles(ctx context.Context, limit int) ([]*model.ImageFile, error) {
+	var files []*model.ImageFile
+	_, err := u.db.QueryContext(ctx, &files, `
+		(SELECT i.id AS image_id, false AS variant, 0 AS size, i.content_type, i.hash, i.file
+		 FROM images i
+		 WHERE i.file IS NOT NULL)
+		UNION ALL
+		(SELECT v.image_id, true, v.size, v.content_type, i.hash, v.file
+		 FROM image_variants v
+		 JOIN images i ON i.id = v.image_id
+		 WHERE v.file IS NOT NULL)
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, tracerr.Errorf("failed get inline image files: %w", err)
+	}
+
+	return files, nil
+}
+
+// MoveImageFileToStorage запоминает ключ перенесённого файла и удаляет его содержимое из таблицы.
+func (u *UserRepository) MoveImageFileToStorage(ctx context.Context, file *model.ImageFile, storageKey string) error {
+	var err error
+	if file.Variant {
+		_, err = u.db.ExecContext(ctx, `
+			UPDATE image_variants SET storage_key = ?, file = NULL
+			WHERE image_id = ? AND size = ? AND content_type = ?`,
+			storageKey, file.ImageID, file.Size, file.ContentType)
+	} else {
+		_, err = u.db.ExecContext(ctx, `UPDATE images SET storage_key = ?, file = NULL WHERE id = ?`,
+			storageKey, file.ImageID)
 	}
-	return user.Image.File, user.Image.ContentType, nil
+	if err != nil {
+		return tracerr.Errorf("failed move image file to storage: %w", err)
+	}
+
+	return nil
+}
+
+// DownloadImage отдаёт вариант аватара одного из contentTypes с наименьшей стороной не меньше size,
+// а если такого нет — самый большой. Для изображений, загруженных до появления вариантов, отдаётся исходный файл.
+func (u *UserRepository) DownloadImage(ctx context.Context, userId int, size int, contentTypes []string) (*model.ImageContent, error) {
+	image := model.Image{}
+	_, err := u.db.QueryOneContext(ctx, &image, `
+		SELECT i.id, i.hash, i.created_at
+		FROM users u
+		JOIN images i ON i.id = u.image_id
+		WHERE u.id = ?`, userId)
+	if err != nil {
+		if isNoRowsError(err) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Wrap(err)
+	}
+
+	variant := model.ImageVariant{}
+	err = u.db.ModelContext(ctx, &variant).
+		Where("image_id = ?", image.ID).
+		Where("content_type IN (?)", pg.In(contentTypes)).
+		OrderExpr("size >= ? DESC", size).
+		OrderExpr("CASE WHEN size >= ? THEN size ELSE -size END", size).
+		Limit(1).
+		Select()
+	if err == nil {
+		return &model.ImageContent{
+			Hash:        image.Hash,
+			Size:        variant.Size,
+			ContentType: variant.ContentType,
+			File:        variant.File,
+			StorageKey:  variant.StorageKey,
+			CreatedAt:   image.CreatedAt,
+		}, nil
+	}
+	if !isNoRowsError(err) {
+		return nil, tracerr.Wrap(err)
+	}
+
+	if err := u.db.ModelContext(ctx, &image).WherePK().Select(); err != nil {
+		if isNoRowsError(err) {
+			return nil, repo.ErrNotFound
+		}
+		return nil, tracerr.Wrap(err)
+	}
+
+	return &model.ImageContent{
+		Hash:        image.Hash,
+		ContentType: image.ContentType,
+		File:        image.File,
+		StorageKey:  image.StorageKey,
+		CreatedAt:   image.CreatedAt,
+	}, nil
 }
@@ -0,0 +1,66 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+)
+
+var (
+	_ repo.EventBus[struct{}] = (*Broker[struct{}])(nil)
+)
+
+// Broker раздаёт события подписчикам внутри процесса. Медленный подписчик не задерживает
+// публикацию: если его буфер заполнен, событие для него теряется.
+type Broker[T any] struct {
+	mu     sync.RWMutex
+	topics map[string]map[chan T]struct{}
+	buffer int
+}
+
+func NewBroker[T any](buffer int) *Broker[T] {
+	return &Broker[T]{
+		topics: make(map[string]map[chan T]struct{}),
+		buffer: buffer,
+	}
+}
+
+func (b *Broker[T]) Publish(topic string, event T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.topics[topic] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe возвращает канал событий темы. Канал закрывается, когда отменяется ctx.
+func (b *Broker[T]) Subscribe(ctx context.Context, topic string) <-chan T {
+	ch := make(chan T, b.buffer)
+
+	b.mu.Lock()
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[chan T]struct{})
+	}
+	b.topics[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.topics[topic], ch)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+		b.mu.Unlock()
+
+		close(ch)
+	}()
+
+	return ch
+}
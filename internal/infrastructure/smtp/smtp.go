@@ -1,11 +1,19 @@
 package smtp
 
 import (
+	"context"
 	"crypto/tls"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/ztrue/tracerr"
 	"gopkg.in/gomail.v2"
 )
 
+var (
+	_ repo.Mailer = (*Sender)(nil)
+)
+
 type Config struct {
 	Host         string
 	Port         int
@@ -21,53 +29,48 @@ type Sender struct {
 	cfg    *Config
 }
 
-func NewSender(cfg *Config) (*Sender, error) {
+// NewSender не подключается к серверу: недоступный SMTP не должен мешать запуску,
+// письма дождутся его в очереди.
+func NewSender(cfg *Config) *Sender {
 	d := gomail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
 	d.TLSConfig = &tls.Config{InsecureSkipVerify: cfg.SSL}
 
-	// Check connection to SMTP server
-	closer, err := d.Dial()
-	if err != nil {
-		return nil, tracerr.Errorf("can't check smtp connection: %w", err)
-	}
-	if err := closer.Close(); err != nil {
-		return nil, tracerr.Errorf("can't check smtp connection: %w", err)
-	}
-
 	return &Sender{
 		dialer: d,
 		cfg:    cfg,
-	}, nil
+	}
 }
 
-func (s *Sender) SendPlainMessage(
-	subject string, content string, to string, attachments ...string,
-) error {
-	m := gomail.NewMessage()
-	m.SetHeader("From", s.cfg.From)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/plain", content)
-
-	for _, attachment := range attachments {
-		m.Attach(attachment)
+// Ping проверяет подключение к SMTP-серверу.
+func (s *Sender) Ping() error {
+	closer, err := s.dialer.Dial()
+	if err != nil {
+		return tracerr.Errorf("can't check smtp connection: %w", err)
+	}
+	if err := closer.Close(); err != nil {
+		return tracerr.Errorf("can't check smtp connection: %w", err)
 	}
 
-	return s.dialer.DialAndSend(m)
+	return nil
 }
 
-func (s *Sender) SendHtmlMessage(
-	subject string, content string, to string, attachments ...string,
-) error {
-	m := gomail.NewMessage()
-	m.SetHeader("From", s.cfg.From)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", content)
+func (s *Sender) Send(ctx context.Context, message *model.MailMessage) error {
+	return s.dialer.DialAndSend(NewMessage(s.cfg.From, message))
+}
 
-	for _, attachment := range attachments {
-		m.Attach(attachment)
+// NewMessage собирает письмо multipart/alternative. Для писем с отпиской добавляются
+// заголовки отписки в один клик по RFC 8058.
+func NewMessage(from string, message *model.MailMessage) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetHeader("To", message.To)
+	m.SetHeader("Subject", message.Subject)
+	if message.Unsubscribe != "" {
+		m.SetHeader("List-Unsubscribe", "<"+message.Unsubscribe+">")
+		m.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
 	}
+	m.SetBody("text/plain", message.Text)
+	m.AddAlternative("text/html", message.HTML)
 
-	return s.dialer.DialAndSend(m)
+	return m
 }
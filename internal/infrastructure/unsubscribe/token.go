@@ -0,0 +1,54 @@
+package unsubscribe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid unsubscribe token")
+)
+
+// Signer выдаёт токены отписки вида <userId>.<категория>.<подпись>. Срока действия у токена нет:
+// ссылка из старого письма должна работать всегда.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+func (s *Signer) Token(userId int, category string) string {
+	return fmt.Sprintf("%d.%s.%s", userId, category, s.signature(userId, category))
+}
+
+// Parse проверяет подпись и возвращает пользователя и категорию.
+func (s *Signer) Parse(token string) (int, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, "", ErrInvalidToken
+	}
+
+	userId, err := strconv.Atoi(parts[0])
+	if err != nil || userId <= 0 {
+		return 0, "", ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(s.signature(userId, parts[1])), []byte(parts[2])) {
+		return 0, "", ErrInvalidToken
+	}
+
+	return userId, parts[1], nil
+}
+
+func (s *Signer) signature(userId int, category string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "unsubscribe:%d:%s", userId, category)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package unsubscribe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSignerRoundTrip(t *testing.T) {
+	signer := NewSigner("unsubscribe-secret")
+
+	tests := []struct {
+		userId   int
+		category string
+	}{
+		{userId: 1, category: "ACHIEVEMENTS"},
+		{userId: 42, category: "GAME_INVITES"},
+		{userId: 1 << 30, category: "DIGEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			token := signer.Token(tt.userId, tt.category)
+
+			userId, category, err := signer.Parse(token)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", token, err)
+			}
+			if userId != tt.userId || category != tt.category {
+				t.Fatalf("Parse(%q) = (%d, %q), want (%d, %q)", token, userId, category, tt.userId, tt.category)
+			}
+		})
+	}
+}
+
+func TestSignerRejectsInvalidTokens(t *testing.T) {
+	signer := NewSigner("unsubscribe-secret")
+	valid := signer.Token(42, "ACHIEVEMENTS")
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "missing signature", token: parts[0] + "." + parts[1]},
+		{name: "extra part", token: valid + ".x"},
+		{name: "another user", token: "43." + parts[1] + "." + parts[2]},
+		{name: "another category", token: parts[0] + ".DIGEST." + parts[2]},
+		{name: "tampered signature", token: parts[0] + "." + parts[1] + "." + strings.ToUpper(parts[2])},
+		{name: "empty signature", token: parts[0] + "." + parts[1] + "."},
+		{name: "non-numeric user", token: "abc." + parts[1] + "." + parts[2]},
+		{name: "zero user", token: "0." + parts[1] + "." + NewSigner("unsubscribe-secret").signature(0, parts[1])},
+		{name: "negative user", token: "-1." + parts[1] + "." + NewSigner("unsubscribe-secret").signature(-1, parts[1])},
+		{name: "signed with another secret", token: NewSigner("other-secret").Token(42, "ACHIEVEMENTS")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := signer.Parse(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("Parse(%q) err = %v, want ErrInvalidToken", tt.token, err)
+			}
+		})
+	}
+}
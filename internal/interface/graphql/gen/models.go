@@ -16,6 +16,22 @@ type Achievement struct {
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+type AuditEvent struct {
+	ID        int   `json:"id"`
+	ActorID   *int  `json:"actorId,omitempty"`
+	ActorRole *Role `json:"actorRole,omitempty"`
+	//  Администратор, действовавший от имени actorId по токену имперсонации
+	ImpersonatorID *int           `json:"impersonatorId,omitempty"`
+	Action         AuditAction    `json:"action"`
+	TargetType     AuditTarget    `json:"targetType"`
+	TargetID       string         `json:"targetId"`
+	Before         map[string]any `json:"before,omitempty"`
+	After          map[string]any `json:"after,omitempty"`
+	IP             string         `json:"ip"`
+	RequestID      string         `json:"requestId"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
 type AuthenticateUserInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -26,24 +42,54 @@ type AuthenticateUserOutput struct {
 	Error *Error  `json:"error,omitempty"`
 }
 
+type ConfirmEmailChangeInput struct {
+	Code string `json:"code"`
+}
+
+type ConfirmEmailChangeOutput struct {
+	User  *User  `json:"user,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+type DeleteAvatarOutput struct {
+	User  *User  `json:"user,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
 type FinishGameInput struct {
-	RoomID        string `json:"RoomId"`
-	FromUserID    int    `json:"FromUserId"`
-	SecondsInGame int    `json:"SecondsInGame"`
+	RoomID string `json:"RoomId"`
+	//  Игрок определяется по токену. Если передан, должен совпадать с ним
+	FromUserID *int `json:"FromUserId,omitempty"`
+	//  Сколько секунд игрок провёл в игре. Не может превышать время с начала игры по часам сервера
+	SecondsInGame int `json:"SecondsInGame"`
 }
 
 type FinishGameOutput struct {
 	RoomID     string `json:"RoomId"`
 	WinnerID   int    `json:"WinnerId"`
 	ResultText string `json:"ResultText"`
+	//  Пусто, пока результат прислал только один игрок или итог ждёт судью
+	Outcome *GameOutcome `json:"Outcome,omitempty"`
+	//  Итог вынесет судья (judgeGame), WinnerId пока 0
+	AwaitingJudge bool   `json:"AwaitingJudge"`
+	Error         *Error `json:"error,omitempty"`
 }
 
 type GameStatus struct {
-	RoomID   string    `json:"RoomId"`
-	Status   string    `json:"Status"`
-	WinnerID *int      `json:"WinnerId,omitempty"`
-	StartAt  time.Time `json:"StartAt"`
-	FinishAt time.Time `json:"FinishAt"`
+	RoomID   string `json:"RoomId"`
+	Status   string `json:"Status"`
+	WinnerID *int   `json:"WinnerId,omitempty"`
+	//  Итог игры, пусто, пока игра идёт или итог ждёт судью
+	Outcome *GameOutcome `json:"Outcome,omitempty"`
+	//  Итог вынесет судья (judgeGame)
+	AwaitingJudge bool      `json:"AwaitingJudge"`
+	StartAt       time.Time `json:"StartAt"`
+	FinishAt      time.Time `json:"FinishAt"`
+	//  Время переходов жизненного цикла: PENDING → STARTED → FINISHED или PENDING → DECLINED
+	CreatedAt  time.Time  `json:"CreatedAt"`
+	StartedAt  *time.Time `json:"StartedAt,omitempty"`
+	DeclinedAt *time.Time `json:"DeclinedAt,omitempty"`
+	FinishedAt *time.Time `json:"FinishedAt,omitempty"`
 }
 
 type GameStatusInput struct {
@@ -51,7 +97,8 @@ type GameStatusInput struct {
 }
 
 type GameStatusOutput struct {
-	GameStatus *GameStatus `json:"GameStatus"`
+	GameStatus *GameStatus `json:"GameStatus,omitempty"`
+	Error      *Error      `json:"error,omitempty"`
 }
 
 type GetAllUsersInput struct {
@@ -64,13 +111,35 @@ type GetAllUsersOutput struct {
 	Error *Error  `json:"error,omitempty"`
 }
 
+type GetAuditEventsInput struct {
+	ActorID        *int         `json:"actorId,omitempty"`
+	ImpersonatorID *int         `json:"impersonatorId,omitempty"`
+	Action         *AuditAction `json:"action,omitempty"`
+	TargetType     *AuditTarget `json:"targetType,omitempty"`
+	TargetID       *string      `json:"targetId,omitempty"`
+	From           *time.Time   `json:"from,omitempty"`
+	To             *time.Time   `json:"to,omitempty"`
+	//  Курсор из nextCursor предыдущей страницы
+	After *string `json:"after,omitempty"`
+	Limit int     `json:"limit"`
+}
+
+type GetAuditEventsOutput struct {
+	Events     []*AuditEvent `json:"events"`
+	NextCursor *string       `json:"nextCursor,omitempty"`
+	Error      *Error        `json:"error,omitempty"`
+}
+
 type GetGamesStatsInput struct {
 	UserID int `json:"userId"`
 }
 
 type GetGamesStatsOutput struct {
-	GamesAmount     int                `json:"gamesAmount"`
-	WinsAmount      int                `json:"winsAmount"`
+	GamesAmount int `json:"gamesAmount"`
+	WinsAmount  int `json:"winsAmount"`
+	DrawsAmount int `json:"drawsAmount"`
+	//  Игры, которые пользователь бросил и проиграл из-за этого
+	ForfeitsAmount  int                `json:"forfeitsAmount"`
 	WinsPercents    float64            `json:"winsPercents"`
 	MetaTopicsStats []*MetaTopicsStats `json:"metaTopicsStats,omitempty"`
 	Error           *Error             `json:"error,omitempty"`
@@ -88,6 +157,35 @@ type GetMetatopicsOutput struct {
 	Metatopics []*Metatopic `json:"metatopics"`
 }
 
+type GetModerationHistoryInput struct {
+	UserID int `json:"userId"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type GetModerationHistoryOutput struct {
+	Actions       []*ModerationActionRecord `json:"actions"`
+	OffenderStats *OffenderStats            `json:"offenderStats,omitempty"`
+	Error         *Error                    `json:"error,omitempty"`
+}
+
+type GetNotificationPreferencesOutput struct {
+	Preferences []*NotificationPreference `json:"preferences"`
+	Error       *Error                    `json:"error,omitempty"`
+}
+
+type GetOutboxEmailsInput struct {
+	//  Пустой список — все статусы
+	Statuses []EmailStatus `json:"statuses"`
+	Limit    int           `json:"limit"`
+	Offset   int           `json:"offset"`
+}
+
+type GetOutboxEmailsOutput struct {
+	Emails []*OutboxEmail `json:"emails"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
 type GetTopicInput struct {
 	ID int `json:"id"`
 }
@@ -119,11 +217,61 @@ type GetUserOutput struct {
 	Error *Error `json:"error,omitempty"`
 }
 
+type ImpersonateUserInput struct {
+	UserID int    `json:"userId"`
+	Reason string `json:"reason"`
+}
+
+type ImpersonateUserOutput struct {
+	Jwt       *string    `json:"jwt,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Error     *Error     `json:"error,omitempty"`
+}
+
+type JudgeGameInput struct {
+	RoomID string `json:"RoomId"`
+	//  Победитель, null — ничья
+	WinnerID *int `json:"WinnerId,omitempty"`
+}
+
+type JudgeGameOutput struct {
+	GameStatus *GameStatus `json:"GameStatus,omitempty"`
+	Error      *Error      `json:"error,omitempty"`
+}
+
+type ListReportsInput struct {
+	PageSize       int            `json:"pageSize"`
+	PageNumber     int            `json:"pageNumber"`
+	Statuses       []ReportStatus `json:"statuses"`
+	ReportedUserID *int           `json:"reportedUserId,omitempty"`
+}
+
+type ListReportsOutput struct {
+	PageSize   int       `json:"pageSize"`
+	PageNumber int       `json:"pageNumber"`
+	PageCount  int       `json:"pageCount"`
+	Reports    []*Report `json:"reports"`
+	Error      *Error    `json:"error,omitempty"`
+}
+
+type MarkNotificationsReadInput struct {
+	//  Пустой список — отметить все
+	Ids []int `json:"ids"`
+}
+
+type MarkNotificationsReadOutput struct {
+	UnreadCount int    `json:"unreadCount"`
+	Error       *Error `json:"error,omitempty"`
+}
+
 type MetaTopicsStats struct {
-	MetaTopic    string  `json:"metaTopic"`
-	GamesAmount  int     `json:"gamesAmount"`
-	WinsAmount   int     `json:"winsAmount"`
-	WinsPercents float64 `json:"winsPercents"`
+	MetaTopic   string `json:"metaTopic"`
+	GamesAmount int    `json:"gamesAmount"`
+	WinsAmount  int    `json:"winsAmount"`
+	DrawsAmount int    `json:"drawsAmount"`
+	//  Игры, которые пользователь бросил и проиграл из-за этого
+	ForfeitsAmount int     `json:"forfeitsAmount"`
+	WinsPercents   float64 `json:"winsPercents"`
 }
 
 type Metatopic struct {
@@ -132,9 +280,71 @@ type Metatopic struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type ModerationActionRecord struct {
+	ID          int              `json:"id"`
+	ReportID    *int             `json:"reportId,omitempty"`
+	UserID      int              `json:"userId"`
+	ModeratorID int              `json:"moderatorId"`
+	Action      ModerationAction `json:"action"`
+	Comment     string           `json:"comment"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
 type Mutation struct {
 }
 
+type MyNotificationsInput struct {
+	//  Курсор из nextCursor предыдущей страницы
+	After      *string `json:"after,omitempty"`
+	Limit      int     `json:"limit"`
+	UnreadOnly *bool   `json:"unreadOnly,omitempty"`
+}
+
+type MyNotificationsOutput struct {
+	Notifications []*Notification `json:"notifications"`
+	NextCursor    *string         `json:"nextCursor,omitempty"`
+	Error         *Error          `json:"error,omitempty"`
+}
+
+type Notification struct {
+	ID   int              `json:"id"`
+	Type NotificationType `json:"type"`
+	//  Данные события, набор полей зависит от type
+	Payload   map[string]any `json:"payload"`
+	Read      bool           `json:"read"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type NotificationPreference struct {
+	Category NotificationCategory `json:"category"`
+	Enabled  bool                 `json:"enabled"`
+}
+
+type NotificationPreferenceInput struct {
+	Category NotificationCategory `json:"category"`
+	Enabled  bool                 `json:"enabled"`
+}
+
+// Сводка по нарушителю: сколько на него жалоб, предупреждений и банов за всё время
+type OffenderStats struct {
+	ReportsCount  int `json:"reportsCount"`
+	WarningsCount int `json:"warningsCount"`
+	BansCount     int `json:"bansCount"`
+}
+
+type OutboxEmail struct {
+	ID            int                  `json:"id"`
+	Recipient     string               `json:"recipient"`
+	Category      NotificationCategory `json:"category"`
+	Subject       string               `json:"subject"`
+	Status        EmailStatus          `json:"status"`
+	Attempts      int                  `json:"attempts"`
+	LastError     *string              `json:"lastError,omitempty"`
+	NextAttemptAt time.Time            `json:"nextAttemptAt"`
+	CreatedAt     time.Time            `json:"createdAt"`
+	SentAt        *time.Time           `json:"sentAt,omitempty"`
+}
+
 type Query struct {
 }
 
@@ -147,15 +357,44 @@ type RecoveryPasswordOutput struct {
 }
 
 type RegisterUserInput struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Username string  `json:"username"`
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	Locale   *Locale `json:"locale,omitempty"`
 }
 
 type RegisterUserOutput struct {
-	User  *User   `json:"user,omitempty"`
-	Jwt   *string `json:"jwt,omitempty"`
-	Error *Error  `json:"error,omitempty"`
+	User               *User               `json:"user,omitempty"`
+	Jwt                *string             `json:"jwt,omitempty"`
+	Error              *Error              `json:"error,omitempty"`
+	PasswordViolations []PasswordViolation `json:"passwordViolations,omitempty"`
+}
+
+type Report struct {
+	ID             int                       `json:"id"`
+	ReporterID     int                       `json:"reporterId"`
+	ReportedUserID int                       `json:"reportedUserId"`
+	GameRoomID     string                    `json:"gameRoomId"`
+	Reason         ReportReason              `json:"reason"`
+	Comment        string                    `json:"comment"`
+	Status         ReportStatus              `json:"status"`
+	Resolution     *ModerationAction         `json:"resolution,omitempty"`
+	CreatedAt      time.Time                 `json:"createdAt"`
+	ResolvedAt     *time.Time                `json:"resolvedAt,omitempty"`
+	Actions        []*ModerationActionRecord `json:"actions"`
+	OffenderStats  *OffenderStats            `json:"offenderStats"`
+}
+
+type ReportUserInput struct {
+	UserID     int          `json:"userId"`
+	GameRoomID string       `json:"gameRoomId"`
+	Reason     ReportReason `json:"reason"`
+	Comment    *string      `json:"comment,omitempty"`
+}
+
+type ReportUserOutput struct {
+	Report *Report `json:"report,omitempty"`
+	Error  *Error  `json:"error,omitempty"`
 }
 
 type ResetPasswordInput struct {
@@ -165,16 +404,53 @@ type ResetPasswordInput struct {
 }
 
 type ResetPasswordOutput struct {
+	Error              *Error              `json:"error,omitempty"`
+	PasswordViolations []PasswordViolation `json:"passwordViolations,omitempty"`
+}
+
+type ResolveReportInput struct {
+	ReportID int              `json:"reportId"`
+	Action   ModerationAction `json:"action"`
+	Comment  *string          `json:"comment,omitempty"`
+}
+
+type ResolveReportOutput struct {
+	Report *Report `json:"report,omitempty"`
+	Error  *Error  `json:"error,omitempty"`
+}
+
+type RetryOutboxEmailInput struct {
+	ID int `json:"id"`
+}
+
+type RetryOutboxEmailOutput struct {
+	Email *OutboxEmail `json:"email,omitempty"`
+	Error *Error       `json:"error,omitempty"`
+}
+
+type RevertEmailChangeInput struct {
+	Token string `json:"token"`
+}
+
+type RevertEmailChangeOutput struct {
 	Error *Error `json:"error,omitempty"`
 }
 
 type StartGameInput struct {
-	RoomID     string `json:"RoomId"`
-	FromUserID int    `json:"FromUserId"`
+	RoomID string `json:"RoomId"`
+	//  Игрок определяется по токену. Если передан, должен совпадать с ним
+	FromUserID *int `json:"FromUserId,omitempty"`
+	//  Кого вызвать на игру: ему придёт уведомление. Учитывается, только пока второй игрок не пришёл
+	OpponentID *int `json:"OpponentId,omitempty"`
 }
 
 type StartGameOutput struct {
-	GameStatus *GameStatus `json:"GameStatus"`
+	GameStatus *GameStatus `json:"GameStatus,omitempty"`
+	Error      *Error      `json:"error,omitempty"`
+}
+
+// Подписки работают по WebSocket (graphql-ws), токен передаётся в payload connection_init как Authorization.
+type Subscription struct {
 }
 
 type SuggestTopicInput struct {
@@ -205,6 +481,11 @@ type TopicMetatopics struct {
 	Metatopics []*Metatopic `json:"metatopics"`
 }
 
+type UnreadNotificationCountOutput struct {
+	Count int    `json:"count"`
+	Error *Error `json:"error,omitempty"`
+}
+
 type UpdateEmailInput struct {
 	ID       int    `json:"id"`
 	Email    string `json:"email"`
@@ -215,6 +496,15 @@ type UpdateEmailOutput struct {
 	Error *Error `json:"error,omitempty"`
 }
 
+type UpdateNotificationPreferencesInput struct {
+	Preferences []*NotificationPreferenceInput `json:"preferences"`
+}
+
+type UpdateNotificationPreferencesOutput struct {
+	Preferences []*NotificationPreference `json:"preferences"`
+	Error       *Error                    `json:"error,omitempty"`
+}
+
 type UpdatePasswordInput struct {
 	ID          int    `json:"id"`
 	OldPassword string `json:"oldPassword"`
@@ -222,7 +512,8 @@ type UpdatePasswordInput struct {
 }
 
 type UpdatePasswordOutput struct {
-	Error *Error `json:"error,omitempty"`
+	Error              *Error              `json:"error,omitempty"`
+	PasswordViolations []PasswordViolation `json:"passwordViolations,omitempty"`
 }
 
 type UpdateTopicInput struct {
@@ -237,13 +528,21 @@ type UpdateTopicOutput struct {
 type UpdateUserInput struct {
 	ID       int     `json:"id"`
 	Username *string `json:"username,omitempty"`
+	//  Одно из изображений, которые пользователь уже загружал или ставил себе
 	ImageID  *int    `json:"imageId,omitempty"`
 	Password *string `json:"password,omitempty"`
 	Email    *string `json:"email,omitempty"`
+	Locale   *Locale `json:"locale,omitempty"`
 }
 
 type UpdateUserOutput struct {
-	User  *User  `json:"user"`
+	User               *User               `json:"user"`
+	Error              *Error              `json:"error,omitempty"`
+	PasswordViolations []PasswordViolation `json:"passwordViolations,omitempty"`
+}
+
+type UploadAvatarOutput struct {
+	User  *User  `json:"user,omitempty"`
 	Error *Error `json:"error,omitempty"`
 }
 
@@ -255,6 +554,7 @@ type User struct {
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	ImageURL  string    `json:"imageUrl"`
+	Locale    Locale    `json:"locale"`
 }
 
 type UserAchievementsInput struct {
@@ -277,15 +577,171 @@ type VerifyRecoveryCodeOutput struct {
 	Error *Error `json:"error,omitempty"`
 }
 
+type AuditAction string
+
+const (
+	AuditActionUserUpdated                   AuditAction = "USER_UPDATED"
+	AuditActionUserEmailChanged              AuditAction = "USER_EMAIL_CHANGED"
+	AuditActionUserEmailChangeRequested      AuditAction = "USER_EMAIL_CHANGE_REQUESTED"
+	AuditActionUserEmailChangeReverted       AuditAction = "USER_EMAIL_CHANGE_REVERTED"
+	AuditActionUserPasswordChanged           AuditAction = "USER_PASSWORD_CHANGED"
+	AuditActionUserPasswordRecoveryRequested AuditAction = "USER_PASSWORD_RECOVERY_REQUESTED"
+	AuditActionUserPasswordReset             AuditAction = "USER_PASSWORD_RESET"
+	AuditActionTopicUpdated                  AuditAction = "TOPIC_UPDATED"
+	AuditActionReportResolved                AuditAction = "REPORT_RESOLVED"
+	AuditActionUserAvatarChanged             AuditAction = "USER_AVATAR_CHANGED"
+	AuditActionUserImpersonated              AuditAction = "USER_IMPERSONATED"
+	AuditActionImpersonatedRequest           AuditAction = "IMPERSONATED_REQUEST"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionUserUpdated,
+	AuditActionUserEmailChanged,
+	AuditActionUserEmailChangeRequested,
+	AuditActionUserEmailChangeReverted,
+	AuditActionUserPasswordChanged,
+	AuditActionUserPasswordRecoveryRequested,
+	AuditActionUserPasswordReset,
+	AuditActionTopicUpdated,
+	AuditActionReportResolved,
+	AuditActionUserAvatarChanged,
+	AuditActionUserImpersonated,
+	AuditActionImpersonatedRequest,
+}
+
+func (e AuditAction) IsValid() bool {
+	switch e {
+	case AuditActionUserUpdated, AuditActionUserEmailChanged, AuditActionUserEmailChangeRequested, AuditActionUserEmailChangeReverted, AuditActionUserPasswordChanged, AuditActionUserPasswordRecoveryRequested, AuditActionUserPasswordReset, AuditActionTopicUpdated, AuditActionReportResolved, AuditActionUserAvatarChanged, AuditActionUserImpersonated, AuditActionImpersonatedRequest:
+		return true
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+func (e *AuditAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditAction", str)
+	}
+	return nil
+}
+
+func (e AuditAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type AuditTarget string
+
+const (
+	AuditTargetUser   AuditTarget = "USER"
+	AuditTargetTopic  AuditTarget = "TOPIC"
+	AuditTargetReport AuditTarget = "REPORT"
+)
+
+var AllAuditTarget = []AuditTarget{
+	AuditTargetUser,
+	AuditTargetTopic,
+	AuditTargetReport,
+}
+
+func (e AuditTarget) IsValid() bool {
+	switch e {
+	case AuditTargetUser, AuditTargetTopic, AuditTargetReport:
+		return true
+	}
+	return false
+}
+
+func (e AuditTarget) String() string {
+	return string(e)
+}
+
+func (e *AuditTarget) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditTarget", str)
+	}
+	return nil
+}
+
+func (e AuditTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EmailStatus string
+
+const (
+	EmailStatusPending EmailStatus = "PENDING"
+	EmailStatusSent    EmailStatus = "SENT"
+	//  Не доставлено после всех попыток
+	EmailStatusDead EmailStatus = "DEAD"
+)
+
+var AllEmailStatus = []EmailStatus{
+	EmailStatusPending,
+	EmailStatusSent,
+	EmailStatusDead,
+}
+
+func (e EmailStatus) IsValid() bool {
+	switch e {
+	case EmailStatusPending, EmailStatusSent, EmailStatusDead:
+		return true
+	}
+	return false
+}
+
+func (e EmailStatus) String() string {
+	return string(e)
+}
+
+func (e *EmailStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EmailStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EmailStatus", str)
+	}
+	return nil
+}
+
+func (e EmailStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // Чтобы понять какая придёт, смотри описание метода API
 type Error string
 
 const (
-	ErrorNotFound           Error = "NOT_FOUND"
-	ErrorValidation         Error = "VALIDATION"
-	ErrorInvalidCredentials Error = "INVALID_CREDENTIALS"
-	ErrorAlreadyExist       Error = "ALREADY_EXIST"
-	ErrorUnauthorized       Error = "UNAUTHORIZED"
+	ErrorNotFound               Error = "NOT_FOUND"
+	ErrorValidation             Error = "VALIDATION"
+	ErrorInvalidCredentials     Error = "INVALID_CREDENTIALS"
+	ErrorAlreadyExist           Error = "ALREADY_EXIST"
+	ErrorUnauthorized           Error = "UNAUTHORIZED"
+	ErrorBanned                 Error = "BANNED"
+	ErrorWeakPassword           Error = "WEAK_PASSWORD"
+	ErrorImageTooLarge          Error = "IMAGE_TOO_LARGE"
+	ErrorUnsupportedImageFormat Error = "UNSUPPORTED_IMAGE_FORMAT"
+	//  В игре уже два игрока
+	ErrorGameFull Error = "GAME_FULL"
+	//  Действие недопустимо в текущем статусе игры, например завершение не начатой игры
+	ErrorInvalidGameState Error = "INVALID_GAME_STATE"
 )
 
 var AllError = []Error{
@@ -294,11 +750,17 @@ var AllError = []Error{
 	ErrorInvalidCredentials,
 	ErrorAlreadyExist,
 	ErrorUnauthorized,
+	ErrorBanned,
+	ErrorWeakPassword,
+	ErrorImageTooLarge,
+	ErrorUnsupportedImageFormat,
+	ErrorGameFull,
+	ErrorInvalidGameState,
 }
 
 func (e Error) IsValid() bool {
 	switch e {
-	case ErrorNotFound, ErrorValidation, ErrorInvalidCredentials, ErrorAlreadyExist, ErrorUnauthorized:
+	case ErrorNotFound, ErrorValidation, ErrorInvalidCredentials, ErrorAlreadyExist, ErrorUnauthorized, ErrorBanned, ErrorWeakPassword, ErrorImageTooLarge, ErrorUnsupportedImageFormat, ErrorGameFull, ErrorInvalidGameState:
 		return true
 	}
 	return false
@@ -325,6 +787,372 @@ func (e Error) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type GameOutcome string
+
+const (
+	GameOutcomeWin  GameOutcome = "WIN"
+	GameOutcomeDraw GameOutcome = "DRAW"
+	//  Один из игроков не прислал результат, победа засчитана другому
+	GameOutcomeForfeit GameOutcome = "FORFEIT"
+	//  Результат не прислал ни один игрок
+	GameOutcomeAbandoned GameOutcome = "ABANDONED"
+	//  Второй игрок не пришёл, игра не началась
+	GameOutcomeNoShow GameOutcome = "NO_SHOW"
+)
+
+var AllGameOutcome = []GameOutcome{
+	GameOutcomeWin,
+	GameOutcomeDraw,
+	GameOutcomeForfeit,
+	GameOutcomeAbandoned,
+	GameOutcomeNoShow,
+}
+
+func (e GameOutcome) IsValid() bool {
+	switch e {
+	case GameOutcomeWin, GameOutcomeDraw, GameOutcomeForfeit, GameOutcomeAbandoned, GameOutcomeNoShow:
+		return true
+	}
+	return false
+}
+
+func (e GameOutcome) String() string {
+	return string(e)
+}
+
+func (e *GameOutcome) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GameOutcome(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GameOutcome", str)
+	}
+	return nil
+}
+
+func (e GameOutcome) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Язык писем пользователя
+type Locale string
+
+const (
+	LocaleRu Locale = "RU"
+	LocaleEn Locale = "EN"
+)
+
+var AllLocale = []Locale{
+	LocaleRu,
+	LocaleEn,
+}
+
+func (e Locale) IsValid() bool {
+	switch e {
+	case LocaleRu, LocaleEn:
+		return true
+	}
+	return false
+}
+
+func (e Locale) String() string {
+	return string(e)
+}
+
+func (e *Locale) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Locale(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Locale", str)
+	}
+	return nil
+}
+
+func (e Locale) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ModerationAction string
+
+const (
+	ModerationActionWarn    ModerationAction = "WARN"
+	ModerationActionBan     ModerationAction = "BAN"
+	ModerationActionDismiss ModerationAction = "DISMISS"
+)
+
+var AllModerationAction = []ModerationAction{
+	ModerationActionWarn,
+	ModerationActionBan,
+	ModerationActionDismiss,
+}
+
+func (e ModerationAction) IsValid() bool {
+	switch e {
+	case ModerationActionWarn, ModerationActionBan, ModerationActionDismiss:
+		return true
+	}
+	return false
+}
+
+func (e ModerationAction) String() string {
+	return string(e)
+}
+
+func (e *ModerationAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ModerationAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ModerationAction", str)
+	}
+	return nil
+}
+
+func (e ModerationAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type NotificationCategory string
+
+const (
+	//  Коды и уведомления о безопасности аккаунта, отключить нельзя
+	NotificationCategorySecurity     NotificationCategory = "SECURITY"
+	NotificationCategoryGameInvites  NotificationCategory = "GAME_INVITES"
+	NotificationCategoryAchievements NotificationCategory = "ACHIEVEMENTS"
+	//  Итоги игр и решения модераторов по предложенным темам
+	NotificationCategoryDigest NotificationCategory = "DIGEST"
+)
+
+var AllNotificationCategory = []NotificationCategory{
+	NotificationCategorySecurity,
+	NotificationCategoryGameInvites,
+	NotificationCategoryAchievements,
+	NotificationCategoryDigest,
+}
+
+func (e NotificationCategory) IsValid() bool {
+	switch e {
+	case NotificationCategorySecurity, NotificationCategoryGameInvites, NotificationCategoryAchievements, NotificationCategoryDigest:
+		return true
+	}
+	return false
+}
+
+func (e NotificationCategory) String() string {
+	return string(e)
+}
+
+func (e *NotificationCategory) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationCategory", str)
+	}
+	return nil
+}
+
+func (e NotificationCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeTopicApproved       NotificationType = "TOPIC_APPROVED"
+	NotificationTypeTopicDeclined       NotificationType = "TOPIC_DECLINED"
+	NotificationTypeGameFinished        NotificationType = "GAME_FINISHED"
+	NotificationTypeAchievementUnlocked NotificationType = "ACHIEVEMENT_UNLOCKED"
+	//  Другой игрок вызвал на игру, в payload roomId и имя вызвавшего
+	NotificationTypeGameChallenge NotificationType = "GAME_CHALLENGE"
+)
+
+var AllNotificationType = []NotificationType{
+	NotificationTypeTopicApproved,
+	NotificationTypeTopicDeclined,
+	NotificationTypeGameFinished,
+	NotificationTypeAchievementUnlocked,
+	NotificationTypeGameChallenge,
+}
+
+func (e NotificationType) IsValid() bool {
+	switch e {
+	case NotificationTypeTopicApproved, NotificationTypeTopicDeclined, NotificationTypeGameFinished, NotificationTypeAchievementUnlocked, NotificationTypeGameChallenge:
+		return true
+	}
+	return false
+}
+
+func (e NotificationType) String() string {
+	return string(e)
+}
+
+func (e *NotificationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationType", str)
+	}
+	return nil
+}
+
+func (e NotificationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Причина, по которой пароль не прошёл проверку (приходит вместе с ошибкой WEAK_PASSWORD)
+type PasswordViolation string
+
+const (
+	PasswordViolationTooShort         PasswordViolation = "TOO_SHORT"
+	PasswordViolationContainsUsername PasswordViolation = "CONTAINS_USERNAME"
+	PasswordViolationContainsEmail    PasswordViolation = "CONTAINS_EMAIL"
+	PasswordViolationBreached         PasswordViolation = "BREACHED"
+)
+
+var AllPasswordViolation = []PasswordViolation{
+	PasswordViolationTooShort,
+	PasswordViolationContainsUsername,
+	PasswordViolationContainsEmail,
+	PasswordViolationBreached,
+}
+
+func (e PasswordViolation) IsValid() bool {
+	switch e {
+	case PasswordViolationTooShort, PasswordViolationContainsUsername, PasswordViolationContainsEmail, PasswordViolationBreached:
+		return true
+	}
+	return false
+}
+
+func (e PasswordViolation) String() string {
+	return string(e)
+}
+
+func (e *PasswordViolation) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PasswordViolation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PasswordViolation", str)
+	}
+	return nil
+}
+
+func (e PasswordViolation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportReason string
+
+const (
+	ReportReasonCheating          ReportReason = "CHEATING"
+	ReportReasonHarassment        ReportReason = "HARASSMENT"
+	ReportReasonHateSpeech        ReportReason = "HATE_SPEECH"
+	ReportReasonSpam              ReportReason = "SPAM"
+	ReportReasonInappropriateName ReportReason = "INAPPROPRIATE_NAME"
+	ReportReasonOther             ReportReason = "OTHER"
+)
+
+var AllReportReason = []ReportReason{
+	ReportReasonCheating,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonSpam,
+	ReportReasonInappropriateName,
+	ReportReasonOther,
+}
+
+func (e ReportReason) IsValid() bool {
+	switch e {
+	case ReportReasonCheating, ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonSpam, ReportReasonInappropriateName, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+func (e ReportReason) String() string {
+	return string(e)
+}
+
+func (e *ReportReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportReason", str)
+	}
+	return nil
+}
+
+func (e ReportReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen     ReportStatus = "OPEN"
+	ReportStatusResolved ReportStatus = "RESOLVED"
+)
+
+var AllReportStatus = []ReportStatus{
+	ReportStatusOpen,
+	ReportStatusResolved,
+}
+
+func (e ReportStatus) IsValid() bool {
+	switch e {
+	case ReportStatusOpen, ReportStatusResolved:
+		return true
+	}
+	return false
+}
+
+func (e ReportStatus) String() string {
+	return string(e)
+}
+
+func (e *ReportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportStatus", str)
+	}
+	return nil
+}
+
+func (e ReportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type Role string
 
 const (
@@ -5,43 +5,69 @@ import (
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
 )
 
 func (m *mutationResolver) StartGame(ctx context.Context, input gen.StartGameInput) (*gen.StartGameOutput, error) {
 	startGameRequest := model.StartGame{
 		RoomID:     input.RoomID,
-		FromUserID: input.FromUserID,
+		FromUserID: mapFromUserId(input.FromUserID),
+		OpponentID: mapFromUserId(input.OpponentID),
 	}
 
 	gameStatus, err := m.useCases.Games.StartGame(ctx, startGameRequest)
 	if err != nil {
-		return &gen.StartGameOutput{}, err
+		if dtoErr := mapGameError(err); dtoErr != nil {
+			return &gen.StartGameOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed start game", err)
 	}
 	return &gen.StartGameOutput{
-		GameStatus: &gen.GameStatus{
-			RoomID:   gameStatus.ID,
-			Status:   string(gameStatus.GameStatusEnum),
-			WinnerID: &gameStatus.WinnerId,
-			StartAt:  gameStatus.StartAt,
-			FinishAt: gameStatus.FinishAt,
-		},
-	}, err
+		GameStatus: mappers.MapGameStatusToDTO(&gameStatus),
+	}, nil
 }
 
 func (m *mutationResolver) FinishGame(ctx context.Context, input gen.FinishGameInput) (*gen.FinishGameOutput, error) {
 	gameResult, err := m.useCases.Games.FinishGame(ctx, model.FinishGame{
 		RoomID:        input.RoomID,
-		FromUserID:    input.FromUserID,
+		FromUserID:    mapFromUserId(input.FromUserID),
 		SecondsInGame: input.SecondsInGame,
 	})
 
 	if err != nil {
-		return &gen.FinishGameOutput{}, err
+		if dtoErr := mapGameError(err); dtoErr != nil {
+			return &gen.FinishGameOutput{RoomID: input.RoomID, Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed finish game", err)
 	}
 
 	return &gen.FinishGameOutput{
-		RoomID:     gameResult.RoomID,
-		WinnerID:   gameResult.WinnerId,
-		ResultText: gameResult.ResultText,
+		RoomID:        gameResult.RoomID,
+		WinnerID:      gameResult.WinnerId,
+		ResultText:    gameResult.ResultText,
+		Outcome:       mappers.MapGameOutcomeToDTO(gameResult.Outcome),
+		AwaitingJudge: gameResult.AwaitingJudge,
+	}, nil
+}
+
+func (m *mutationResolver) JudgeGame(ctx context.Context, input gen.JudgeGameInput) (*gen.JudgeGameOutput, error) {
+	gameStatus, err := m.useCases.Games.JudgeGame(ctx, input.RoomID, input.WinnerID)
+	if err != nil {
+		if dtoErr := mapGameError(err); dtoErr != nil {
+			return &gen.JudgeGameOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed judge game", err)
+	}
+
+	return &gen.JudgeGameOutput{
+		GameStatus: mappers.MapGameStatusToDTO(&gameStatus),
 	}, nil
 }
+
+// mapFromUserId — 0, если клиент не передал игрока и он берётся из токена
+func mapFromUserId(fromUserId *int) int {
+	if fromUserId == nil {
+		return 0
+	}
+	return *fromUserId
+}
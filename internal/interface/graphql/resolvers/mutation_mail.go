@@ -0,0 +1,53 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (m *mutationResolver) RetryOutboxEmail(ctx context.Context, input gen.RetryOutboxEmailInput) (*gen.RetryOutboxEmailOutput, error) {
+	email, err := m.useCases.Mail.RetryOutboxEmail(ctx, input.ID)
+	if err != nil {
+		if dtoErr := mapMailError(err); dtoErr != nil {
+			return &gen.RetryOutboxEmailOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed retry outbox email", err)
+	}
+
+	return &gen.RetryOutboxEmailOutput{
+		Email: mappers.MapOutboxEmailToDTO(email),
+	}, nil
+}
+
+func (m *mutationResolver) UpdateNotificationPreferences(ctx context.Context, input gen.UpdateNotificationPreferencesInput) (*gen.UpdateNotificationPreferencesOutput, error) {
+	preferences, err := m.useCases.Mail.UpdateNotificationPreferences(ctx, mappers.MapNotificationPreferencesFromDTO(input.Preferences))
+	if err != nil {
+		if dtoErr := mapMailError(err); dtoErr != nil {
+			return &gen.UpdateNotificationPreferencesOutput{
+				Preferences: []*gen.NotificationPreference{},
+				Error:       dtoErr,
+			}, nil
+		}
+		return nil, NewResolverError("failed update notification preferences", err)
+	}
+
+	return &gen.UpdateNotificationPreferencesOutput{
+		Preferences: mappers.MapNotificationPreferencesToDTO(preferences),
+	}, nil
+}
+
+func mapMailError(err error) *gen.Error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return mappers.NewDTOError(gen.ErrorNotFound)
+	case errors.Is(err, repo.ErrValidation):
+		return mappers.NewDTOError(gen.ErrorValidation)
+	case errors.Is(err, repo.ErrUnauthorized):
+		return mappers.NewDTOError(gen.ErrorUnauthorized)
+	}
+	return nil
+}
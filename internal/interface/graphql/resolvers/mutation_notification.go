@@ -0,0 +1,19 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+)
+
+func (m *mutationResolver) MarkNotificationsRead(ctx context.Context, input gen.MarkNotificationsReadInput) (*gen.MarkNotificationsReadOutput, error) {
+	unreadCount, err := m.useCases.Notifications.MarkNotificationsRead(ctx, input.Ids)
+	if err != nil {
+		if dtoErr := mapNotificationError(err); dtoErr != nil {
+			return &gen.MarkNotificationsReadOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed mark notifications read", err)
+	}
+
+	return &gen.MarkNotificationsReadOutput{UnreadCount: unreadCount}, nil
+}
@@ -0,0 +1,58 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (m *mutationResolver) ReportUser(ctx context.Context, input gen.ReportUserInput) (*gen.ReportUserOutput, error) {
+	report, err := m.useCases.Reports.ReportUser(ctx, mappers.MapReportUserInputToReport(&input))
+	if err != nil {
+		if dtoErr := mapReportError(err); dtoErr != nil {
+			return &gen.ReportUserOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed to report user", err)
+	}
+
+	return &gen.ReportUserOutput{
+		Report: mappers.MapReportDetailsToReportDTO(report),
+	}, nil
+}
+
+func (m *mutationResolver) ResolveReport(ctx context.Context, input gen.ResolveReportInput) (*gen.ResolveReportOutput, error) {
+	var comment string
+	if input.Comment != nil {
+		comment = *input.Comment
+	}
+
+	report, err := m.useCases.Reports.ResolveReport(ctx, input.ReportID, model.ModerationActionEnum(input.Action), comment)
+	if err != nil {
+		if dtoErr := mapReportError(err); dtoErr != nil {
+			return &gen.ResolveReportOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed to resolve report", err)
+	}
+
+	return &gen.ResolveReportOutput{
+		Report: mappers.MapReportDetailsToReportDTO(report),
+	}, nil
+}
+
+func mapReportError(err error) *gen.Error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return mappers.NewDTOError(gen.ErrorNotFound)
+	case errors.Is(err, repo.ErrValidation):
+		return mappers.NewDTOError(gen.ErrorValidation)
+	case errors.Is(err, repo.ErrAlreadyExist):
+		return mappers.NewDTOError(gen.ErrorAlreadyExist)
+	case errors.Is(err, repo.ErrUnauthorized):
+		return mappers.NewDTOError(gen.ErrorUnauthorized)
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
 )
 
 func (m *mutationResolver) RegisterUser(ctx context.Context, input gen.RegisterUserInput) (*gen.RegisterUserOutput, error) {
@@ -42,6 +46,49 @@ func (m *mutationResolver) UpdateEmail(ctx context.Context, input gen.UpdateEmai
 	return output, err
 }
 
+func (m *mutationResolver) ConfirmEmailChange(ctx context.Context, input gen.ConfirmEmailChangeInput) (*gen.ConfirmEmailChangeOutput, error) {
+	output, err := m.useCases.Users.ConfirmEmailChange(ctx, input)
+	if err != nil {
+		return nil, NewResolverError("failed to confirm email change", err)
+	}
+
+	return output, err
+}
+
+func (m *mutationResolver) RevertEmailChange(ctx context.Context, input gen.RevertEmailChangeInput) (*gen.RevertEmailChangeOutput, error) {
+	err := m.useCases.Users.RevertEmailChange(ctx, input.Token)
+	switch {
+	case err == nil:
+		return &gen.RevertEmailChangeOutput{}, nil
+	case errors.Is(err, repo.ErrValidation):
+		return &gen.RevertEmailChangeOutput{Error: mappers.NewDTOError(gen.ErrorValidation)}, nil
+	case errors.Is(err, repo.ErrNotFound):
+		return &gen.RevertEmailChangeOutput{Error: mappers.NewDTOError(gen.ErrorNotFound)}, nil
+	case errors.Is(err, repo.ErrAlreadyExist):
+		return &gen.RevertEmailChangeOutput{Error: mappers.NewDTOError(gen.ErrorAlreadyExist)}, nil
+	default:
+		return nil, NewResolverError("failed to revert email change", err)
+	}
+}
+
+func (m *mutationResolver) UploadAvatar(ctx context.Context, file graphql.Upload) (*gen.UploadAvatarOutput, error) {
+	output, err := m.useCases.Users.UploadAvatar(ctx, file.File)
+	if err != nil {
+		return nil, NewResolverError("failed to upload avatar", err)
+	}
+
+	return output, nil
+}
+
+func (m *mutationResolver) DeleteAvatar(ctx context.Context) (*gen.DeleteAvatarOutput, error) {
+	output, err := m.useCases.Users.DeleteAvatar(ctx)
+	if err != nil {
+		return nil, NewResolverError("failed to delete avatar", err)
+	}
+
+	return output, nil
+}
+
 func (m mutationResolver) RecoveryPassword(
 	ctx context.Context,
 	input gen.RecoveryPasswordInput,
@@ -67,3 +114,12 @@ func (m mutationResolver) ResetPassword(
 
 	return output, nil
 }
+
+func (m *mutationResolver) ImpersonateUser(ctx context.Context, input gen.ImpersonateUserInput) (*gen.ImpersonateUserOutput, error) {
+	output, err := m.useCases.Users.ImpersonateUser(ctx, input)
+	if err != nil {
+		return nil, NewResolverError("can't impersonate user", err)
+	}
+
+	return output, nil
+}
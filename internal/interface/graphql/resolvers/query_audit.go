@@ -0,0 +1,34 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (q *queryResolver) GetAuditEvents(ctx context.Context, input gen.GetAuditEventsInput) (*gen.GetAuditEventsOutput, error) {
+	events, nextCursor, err := q.useCases.Audit.GetEvents(ctx, mappers.MapGetAuditEventsInputToFilter(&input), input.After, input.Limit)
+	if err != nil {
+		if errors.Is(err, repo.ErrUnauthorized) {
+			return &gen.GetAuditEventsOutput{
+				Events: []*gen.AuditEvent{},
+				Error:  mappers.NewDTOError(gen.ErrorUnauthorized),
+			}, nil
+		}
+		if errors.Is(err, repo.ErrValidation) {
+			return &gen.GetAuditEventsOutput{
+				Events: []*gen.AuditEvent{},
+				Error:  mappers.NewDTOError(gen.ErrorValidation),
+			}, nil
+		}
+		return nil, NewResolverError("failed get audit events", err)
+	}
+
+	return &gen.GetAuditEventsOutput{
+		Events:     mappers.MapAuditEventsToDTO(events),
+		NextCursor: nextCursor,
+	}, nil
+}
@@ -2,22 +2,42 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
 )
 
 func (m *queryResolver) GetGameStatus(ctx context.Context, input gen.GameStatusInput) (*gen.GameStatusOutput, error) {
 	gameStatus, err := m.useCases.Games.GetGameStatus(ctx, input.RoomID)
 	if err != nil {
-		return nil, err
+		if dtoErr := mapGameError(err); dtoErr != nil {
+			return &gen.GameStatusOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed get game status", err)
 	}
 	return &gen.GameStatusOutput{
-		GameStatus: &gen.GameStatus{
-			RoomID:   gameStatus.ID,
-			Status:   string(gameStatus.GameStatusEnum),
-			WinnerID: &gameStatus.WinnerId,
-			StartAt:  gameStatus.StartAt,
-			FinishAt: gameStatus.FinishAt,
-		},
+		GameStatus: mappers.MapGameStatusToDTO(&gameStatus),
 	}, nil
 }
+
+func mapGameError(err error) *gen.Error {
+	var transitionErr *model.GameTransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		return mappers.NewDTOError(gen.ErrorInvalidGameState)
+	case errors.Is(err, model.ErrNotAwaitingJudge):
+		return mappers.NewDTOError(gen.ErrorInvalidGameState)
+	case errors.Is(err, model.ErrGameFull):
+		return mappers.NewDTOError(gen.ErrorGameFull)
+	case errors.Is(err, model.ErrNotGamePlayer), errors.Is(err, repo.ErrUnauthorized):
+		return mappers.NewDTOError(gen.ErrorUnauthorized)
+	case errors.Is(err, repo.ErrNotFound):
+		return mappers.NewDTOError(gen.ErrorNotFound)
+	case errors.Is(err, repo.ErrValidation):
+		return mappers.NewDTOError(gen.ErrorValidation)
+	}
+	return nil
+}
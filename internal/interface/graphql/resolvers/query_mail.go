@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (q *queryResolver) GetOutboxEmails(ctx context.Context, input gen.GetOutboxEmailsInput) (*gen.GetOutboxEmailsOutput, error) {
+	emails, err := q.useCases.Mail.GetOutboxEmails(ctx, mappers.MapEmailStatusesFromDTO(input.Statuses), input.Limit, input.Offset)
+	if err != nil {
+		if dtoErr := mapMailError(err); dtoErr != nil {
+			return &gen.GetOutboxEmailsOutput{
+				Emails: []*gen.OutboxEmail{},
+				Error:  dtoErr,
+			}, nil
+		}
+		return nil, NewResolverError("failed get outbox emails", err)
+	}
+
+	return &gen.GetOutboxEmailsOutput{
+		Emails: mappers.MapOutboxEmailsToDTO(emails),
+	}, nil
+}
+
+func (q *queryResolver) GetNotificationPreferences(ctx context.Context) (*gen.GetNotificationPreferencesOutput, error) {
+	preferences, err := q.useCases.Mail.GetNotificationPreferences(ctx)
+	if err != nil {
+		if dtoErr := mapMailError(err); dtoErr != nil {
+			return &gen.GetNotificationPreferencesOutput{
+				Preferences: []*gen.NotificationPreference{},
+				Error:       dtoErr,
+			}, nil
+		}
+		return nil, NewResolverError("failed get notification preferences", err)
+	}
+
+	return &gen.GetNotificationPreferencesOutput{
+		Preferences: mappers.MapNotificationPreferencesToDTO(preferences),
+	}, nil
+}
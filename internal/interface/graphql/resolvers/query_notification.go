@@ -0,0 +1,51 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (q *queryResolver) MyNotifications(ctx context.Context, input gen.MyNotificationsInput) (*gen.MyNotificationsOutput, error) {
+	unreadOnly := input.UnreadOnly != nil && *input.UnreadOnly
+	notifications, nextCursor, err := q.useCases.Notifications.GetMyNotifications(ctx, input.After, input.Limit, unreadOnly)
+	if err != nil {
+		if dtoErr := mapNotificationError(err); dtoErr != nil {
+			return &gen.MyNotificationsOutput{
+				Notifications: []*gen.Notification{},
+				Error:         dtoErr,
+			}, nil
+		}
+		return nil, NewResolverError("failed get notifications", err)
+	}
+
+	return &gen.MyNotificationsOutput{
+		Notifications: mappers.MapNotificationsToDTO(notifications),
+		NextCursor:    nextCursor,
+	}, nil
+}
+
+func (q *queryResolver) UnreadNotificationCount(ctx context.Context) (*gen.UnreadNotificationCountOutput, error) {
+	count, err := q.useCases.Notifications.UnreadNotificationCount(ctx)
+	if err != nil {
+		if dtoErr := mapNotificationError(err); dtoErr != nil {
+			return &gen.UnreadNotificationCountOutput{Error: dtoErr}, nil
+		}
+		return nil, NewResolverError("failed count notifications", err)
+	}
+
+	return &gen.UnreadNotificationCountOutput{Count: count}, nil
+}
+
+func mapNotificationError(err error) *gen.Error {
+	switch {
+	case errors.Is(err, repo.ErrValidation):
+		return mappers.NewDTOError(gen.ErrorValidation)
+	case errors.Is(err, repo.ErrUnauthorized):
+		return mappers.NewDTOError(gen.ErrorUnauthorized)
+	}
+	return nil
+}
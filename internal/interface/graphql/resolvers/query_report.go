@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+	"math"
+
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (q *queryResolver) ListReports(ctx context.Context, input gen.ListReportsInput) (*gen.ListReportsOutput, error) {
+	reports, rows, err := q.useCases.Reports.ListReports(
+		ctx,
+		mappers.MapReportStatusesToReportStatusEnum(input.Statuses...),
+		input.ReportedUserID,
+		input.PageSize,
+		input.PageNumber,
+	)
+	if err != nil {
+		if dtoErr := mapReportError(err); dtoErr != nil {
+			return &gen.ListReportsOutput{
+				PageSize:   input.PageSize,
+				PageNumber: input.PageNumber,
+				Reports:    []*gen.Report{},
+				Error:      dtoErr,
+			}, nil
+		}
+		return nil, NewResolverError("failed get reports", err)
+	}
+
+	return &gen.ListReportsOutput{
+		PageSize:   input.PageSize,
+		PageNumber: input.PageNumber,
+		PageCount:  int(math.Ceil(float64(rows) / float64(input.PageSize))),
+		Reports:    mappers.MapReportsDetailsToReportsDTO(reports),
+	}, nil
+}
+
+func (q *queryResolver) GetModerationHistory(ctx context.Context, input gen.GetModerationHistoryInput) (*gen.GetModerationHistoryOutput, error) {
+	actions, stats, err := q.useCases.Reports.GetModerationHistory(ctx, input.UserID, input.Limit, input.Offset)
+	if err != nil {
+		if dtoErr := mapReportError(err); dtoErr != nil {
+			return &gen.GetModerationHistoryOutput{
+				Actions: []*gen.ModerationActionRecord{},
+				Error:   dtoErr,
+			}, nil
+		}
+		return nil, NewResolverError("failed get moderation history", err)
+	}
+
+	actionDtos := make([]*gen.ModerationActionRecord, 0, len(actions))
+	for i := range actions {
+		actionDtos = append(actionDtos, mappers.MapModerationActionToDTO(&actions[i]))
+	}
+
+	return &gen.GetModerationHistoryOutput{
+		Actions:       actionDtos,
+		OffenderStats: mappers.MapOffenderStatsToDTO(stats),
+	}, nil
+}
@@ -24,10 +24,14 @@ type queryResolver struct{ *Resolver }
 
 type mutationResolver struct{ *Resolver }
 
+type subscriptionResolver struct{ *Resolver }
+
 func (r *Resolver) Query() gen.QueryResolver { return &queryResolver{r} }
 
 func (r *Resolver) Mutation() gen.MutationResolver { return &mutationResolver{r} }
 
+func (r *Resolver) Subscription() gen.SubscriptionResolver { return &subscriptionResolver{r} }
+
 func NewResolverError(
 	responseError string,
 	originalError error,
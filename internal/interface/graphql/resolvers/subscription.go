@@ -0,0 +1,58 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+)
+
+func (s *subscriptionResolver) GameStatusChanged(ctx context.Context, roomID string) (<-chan *gen.GameStatus, error) {
+	events, err := s.useCases.Games.SubscribeGameStatus(ctx, roomID)
+	if err != nil {
+		return nil, mapSubscriptionError("failed subscribe game status", err)
+	}
+
+	return mapEvents(ctx, events, func(game model.GameStatus) *gen.GameStatus {
+		return mappers.MapGameStatusToDTO(&game)
+	}), nil
+}
+
+func (s *subscriptionResolver) NotificationReceived(ctx context.Context) (<-chan *gen.Notification, error) {
+	events, err := s.useCases.Notifications.SubscribeNotifications(ctx)
+	if err != nil {
+		return nil, mapSubscriptionError("failed subscribe notifications", err)
+	}
+
+	return mapEvents(ctx, events, func(notification model.Notification) *gen.Notification {
+		return mappers.MapNotificationToDTO(&notification)
+	}), nil
+}
+
+// mapEvents переводит события доменной модели в DTO. Выходной канал закрывается вместе с входным.
+func mapEvents[T, D any](ctx context.Context, events <-chan T, mapper func(T) D) <-chan D {
+	output := make(chan D)
+	go func() {
+		defer close(output)
+		for event := range events {
+			select {
+			case output <- mapper(event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// У подписки нет output с полем error, поэтому код ошибки уходит текстом ошибки GraphQL
+func mapSubscriptionError(responseError string, err error) error {
+	if errors.Is(err, repo.ErrUnauthorized) {
+		return NewResolverError(string(gen.ErrorUnauthorized), err)
+	}
+	return NewResolverError(responseError, err)
+}
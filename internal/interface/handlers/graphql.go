@@ -3,35 +3,59 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/debate-io/service-auth/internal/infrastructure/auth"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+	"github.com/debate-io/service-auth/internal/usecases"
+	"github.com/gorilla/websocket"
 	"github.com/inhies/go-bytesize"
 	"github.com/vektah/gqlparser/v2/ast"
 	"go.uber.org/zap"
 )
 
 const (
-	maxUploadSize = 100 * bytesize.MB
 	maxMemorySize = 1 * bytesize.MB
 	cacheQuery    = 1000
+
+	websocketPingInterval     = 10 * time.Second
+	websocketInitTimeout      = 10 * time.Second
+	websocketHandshakeTimeout = 10 * time.Second
 )
 
 func NewGraphqlHandler(
 	logger *zap.Logger,
 	schema graphql.ExecutableSchema,
+	audit *usecases.Audit,
+	authService *auth.AuthService,
+	bans middleware.BanChecker,
+	maxUploadSize int64,
 	isDebug bool,
 ) *handler.Server {
 	srv := handler.New(schema)
 
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: websocketPingInterval,
+		InitTimeout:           websocketInitTimeout,
+		Upgrader: websocket.Upgrader{
+			HandshakeTimeout: websocketHandshakeTimeout,
+			// Origin не ограничиваем, как и CORS для остальных запросов
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		InitFunc: websocketAuth(authService, bans),
+	})
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.MultipartForm{
-		MaxUploadSize: int64(maxUploadSize),
+		MaxUploadSize: maxUploadSize,
 		MaxMemory:     int64(maxMemorySize),
 	})
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](cacheQuery))
@@ -50,6 +74,26 @@ func NewGraphqlHandler(
 		})
 	}
 
+	// Всё, что делается по токену имперсонации, попадает в журнал аудита
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if rc := graphql.GetOperationContext(ctx); rc != nil && rc.Operation != nil {
+			var fields []string
+			for _, selection := range rc.Operation.SelectionSet {
+				if field, ok := selection.(*ast.Field); ok {
+					fields = append(fields, field.Name)
+				}
+			}
+
+			audit.RecordImpersonatedRequest(ctx, "graphql", map[string]interface{}{
+				"operationType": rc.Operation.Operation,
+				"operationName": rc.OperationName,
+				"fields":        fields,
+			})
+		}
+
+		return next(ctx)
+	})
+
 	srv.SetRecoverFunc(func(ctx context.Context, err interface{}) (userMessage error) {
 		logger.Error("recover graphql", zap.Reflect("err:", err))
 
@@ -58,3 +102,21 @@ func NewGraphqlHandler(
 
 	return srv
 }
+
+// websocketAuth берёт токен из payload connection_init: браузер не умеет передавать заголовок
+// Authorization при открытии WebSocket. Без токена остаётся контекст, заполненный AuthMiddleware.
+func websocketAuth(authService *auth.AuthService, bans middleware.BanChecker) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		tokenString := strings.TrimPrefix(payload.Authorization(), "Bearer ")
+		if tokenString == "" {
+			return ctx, &payload, nil
+		}
+
+		claims, err := middleware.ResolveClaims(ctx, authService, bans, tokenString)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid token: %w", err)
+		}
+
+		return context.WithValue(ctx, middleware.JwtClaimsKey, claims), &payload, nil
+	}
+}
@@ -1,15 +1,23 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/imageurl"
+	"github.com/debate-io/service-auth/internal/infrastructure/imaging"
 	"github.com/debate-io/service-auth/internal/interface/server/middleware"
 	"github.com/debate-io/service-auth/internal/registry"
+	"github.com/debate-io/service-auth/internal/usecases"
 	"github.com/go-chi/chi"
 	"github.com/ztrue/tracerr"
 	"go.uber.org/zap"
@@ -25,6 +33,18 @@ type Url string
 const (
 	ImageUrl Url = "/user/{id}/image"
 	PingUrl  Url = "/ping"
+
+	EmailRevertUrl Url = usecases.EmailRevertPath
+	UnsubscribeUrl Url = usecases.UnsubscribePath
+)
+
+const (
+	maxImageSize = 4096
+	// MultipartOverhead — запас на заголовки и границы multipart поверх размера самого файла
+	MultipartOverhead = 64 * 1024
+	// Ссылка без актуальной версии может начать указывать на другой аватар, такие ответы перепроверяются
+	unversionedImageCacheControl = "public, max-age=60, must-revalidate"
+	versionedImageCacheControl   = "public, max-age=31536000, immutable"
 )
 
 func NewRestHandler(
@@ -45,28 +65,43 @@ func (h *RestHandler) GetImageHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	claims := r.Context().Value(middleware.JwtClaimsKey).(*model.Claims)
-	if claims == nil {
-		http.Error(w, repo.ErrUnauthorized.Unwrap().Error(), http.StatusUnauthorized)
+	// Аватары отдаются без JWT, чтобы работали обычные <img>. Если включена подпись ссылок,
+	// доступ ограничивает она
+	if err := h.usecases.Users.CheckImageURL(int(id), r.URL.Query()); err != nil {
+		http.Error(w, repo.ErrUnauthorized.Unwrap().Error(), http.StatusForbidden)
 		return
 	}
 
-	role := claims.Role
-	if role != model.RoleAdmin && role != model.RoleContentManager && role != model.RoleDefaultUser {
-		http.Error(w, repo.ErrUnauthorized.Unwrap().Error(), http.StatusUnauthorized)
-		return
+	size := imaging.Sizes[len(imaging.Sizes)-1]
+	if rawSize := r.URL.Query().Get("size"); rawSize != "" {
+		size, err = strconv.Atoi(rawSize)
+		if err != nil || size <= 0 || size > maxImageSize {
+			http.Error(w, "invalid size", http.StatusBadRequest)
+			return
+		}
 	}
 
-	image, contentType, err := h.usecases.Users.DownloadImage(r.Context(), int(id))
+	image, err := h.usecases.Users.DownloadImage(r.Context(), int(id), size, acceptsWebP(r))
 	if err != nil {
-		fmt.Println(err)
+		if errors.Is(err, repo.ErrNotFound) {
+			http.Error(w, repo.ErrNotFound.Unwrap().Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, tracerr.Unwrap(err).Error(), http.StatusNoContent)
 		return
 	}
 
-	w.Header().Add("Content-Type", fmt.Sprintf("image/%s", contentType))
-	w.WriteHeader(http.StatusOK)
-	w.Write(image)
+	// Содержимое адресуется хешем, поэтому ETag строгий: одинаковый тег — побайтно одинаковый ответ.
+	// Условные запросы и Range обрабатывает http.ServeContent.
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%d-%s"`, image.Hash, image.Size, image.ContentType))
+	w.Header().Set("Content-Type", fmt.Sprintf("image/%s", image.ContentType))
+	w.Header().Set("Cache-Control", h.imageCacheControl(r, image))
+	w.Header().Add("Vary", "Accept")
+	if image.ContentType == imaging.FormatSVG {
+		// SVG открывается браузером как документ, скрипты в нём выполняться не должны
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	}
+	http.ServeContent(w, r, "", image.CreatedAt, bytes.NewReader(image.File))
 }
 
 func (h *RestHandler) PutImageHandler(w http.ResponseWriter, r *http.Request) {
@@ -88,14 +123,23 @@ func (h *RestHandler) PutImageHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	maxBytes := h.usecases.Users.ImageLimits().MaxBytes
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+MultipartOverhead)
+
 	file, _, err := r.FormFile("file")
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Файл слишком большой", http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Не удалось получить файл: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 	defer file.Close()
 
-	data, err := io.ReadAll(file)
+	// Читаем на байт больше лимита, чтобы отличить файл ровно на границе от превышающего её
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
 	if err != nil {
 		http.Error(w, "Не удалось прочитать файл: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -103,7 +147,8 @@ func (h *RestHandler) PutImageHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = h.usecases.Users.UploadImage(r.Context(), int(id), data)
 	if err != nil {
-		http.Error(w, tracerr.Unwrap(err).Error(), http.StatusInternalServerError)
+		message, status := imageUploadError(err)
+		http.Error(w, message, status)
 		return
 	}
 
@@ -111,6 +156,154 @@ func (h *RestHandler) PutImageHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Изображение успешно загружено"))
 }
 
+// imageUploadError подбирает ответ для ошибки загрузки изображения.
+func imageUploadError(err error) (string, int) {
+	switch {
+	case errors.Is(err, imaging.ErrFileTooLarge):
+		return "Файл слишком большой", http.StatusRequestEntityTooLarge
+	case errors.Is(err, imaging.ErrImageTooLarge):
+		return "Слишком большое разрешение изображения", http.StatusRequestEntityTooLarge
+	case errors.Is(err, imaging.ErrUnsupportedFormat):
+		return "Неподдерживаемый формат изображения", http.StatusUnsupportedMediaType
+	case errors.Is(err, repo.ErrValidation):
+		return "Не удалось обработать изображение", http.StatusUnprocessableEntity
+	case errors.Is(err, repo.ErrNotFound):
+		return repo.ErrNotFound.Unwrap().Error(), http.StatusNotFound
+	default:
+		return tracerr.Unwrap(err).Error(), http.StatusInternalServerError
+	}
+}
+
+// AuditImpersonationMiddleware записывает в журнал аудита REST-запросы, сделанные по токену имперсонации.
+func (h *RestHandler) AuditImpersonationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.usecases.Audit.RecordImpersonatedRequest(r.Context(), "rest", map[string]interface{}{
+			"method": r.Method,
+			"path":   r.URL.Path,
+		})
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// imageCacheControl разрешает кешировать ответ навсегда, если ссылка содержит версию текущего аватара.
+// Подписанную ссылку кешируем не дольше срока её действия.
+func (h *RestHandler) imageCacheControl(r *http.Request, image *model.ImageContent) string {
+	query := r.URL.Query()
+	if query.Get("v") == "" || query.Get("v") != imageurl.Version(image.Hash) {
+		return unversionedImageCacheControl
+	}
+
+	if !h.usecases.Users.ImageURLSigned() {
+		return versionedImageCacheControl
+	}
+
+	exp, _ := strconv.ParseInt(query.Get("exp"), 10, 64)
+	maxAge := exp - time.Now().Unix()
+	if maxAge <= 0 {
+		return unversionedImageCacheControl
+	}
+	return fmt.Sprintf("public, max-age=%d, immutable", maxAge)
+}
+
+// acceptsWebP определяет формат ответа: явный параметр format важнее заголовка Accept.
+func acceptsWebP(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == imaging.FormatWebP
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "image/webp")
+}
+
+// GetEmailRevertHandler показывает форму подтверждения отката почты.
+// Сам откат выполняется только POST-запросом, чтобы почтовые сканеры ссылок не срабатывали за пользователя.
+func (h *RestHandler) GetEmailRevertHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "invalid token", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	emailRevertPage.Execute(w, token)
+}
+
+func (h *RestHandler) PostEmailRevertHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.usecases.Users.RevertEmailChange(r.Context(), r.FormValue("token"))
+	switch {
+	case err == nil:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Прежняя почта восстановлена"))
+	case errors.Is(err, repo.ErrValidation):
+		http.Error(w, "invalid token", http.StatusBadRequest)
+	case errors.Is(err, repo.ErrNotFound):
+		http.Error(w, "Ссылка недействительна или устарела", http.StatusNotFound)
+	case errors.Is(err, repo.ErrAlreadyExist):
+		http.Error(w, "Прежняя почта уже занята другим аккаунтом", http.StatusConflict)
+	default:
+		h.logger.Error("failed to revert email change", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+var emailRevertPage = template.Must(template.New("email_revert").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Откат смены почты</title></head>
+<body>
+<form method="POST">
+<input type="hidden" name="token" value="{{.}}">
+<p>Вернуть прежнюю почту аккаунта?</p>
+<button type="submit">Вернуть</button>
+</form>
+</body>
+</html>`))
+
+// GetUnsubscribeHandler показывает форму отписки для перехода по ссылке из письма.
+func (h *RestHandler) GetUnsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "invalid token", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	unsubscribePage.Execute(w, token)
+}
+
+// PostUnsubscribeHandler отписывает в один клик (RFC 8058): почтовый клиент отправляет POST
+// на ссылку из List-Unsubscribe с телом List-Unsubscribe=One-Click, токен остаётся в query.
+func (h *RestHandler) PostUnsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.usecases.Mail.Unsubscribe(r.Context(), r.FormValue("token"))
+	switch {
+	case err == nil:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Вы отписаны от этих писем"))
+	case errors.Is(err, repo.ErrValidation):
+		http.Error(w, "invalid token", http.StatusBadRequest)
+	case errors.Is(err, repo.ErrNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	default:
+		h.logger.Error("failed to unsubscribe", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Отписка от писем</title></head>
+<body>
+<form method="POST">
+<input type="hidden" name="token" value="{{.}}">
+<p>Больше не присылать письма этой категории?</p>
+<button type="submit">Отписаться</button>
+</form>
+</body>
+</html>`))
+
 func (rh *RestHandler) PingHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
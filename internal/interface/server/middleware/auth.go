@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -16,7 +17,37 @@ const (
 	JwtClaimsKey authKey = "jwtClaims"
 )
 
-func AuthMiddleware(auth *auth.AuthService) func(http.Handler) http.Handler {
+var ErrUserBanned = errors.New("user is banned")
+
+// BanChecker сообщает, заблокирован ли пользователь. Токены выдаются надолго, поэтому блокировка
+// проверяется при каждом запросе, а не только при входе.
+type BanChecker interface {
+	IsUserBanned(ctx context.Context, userId int) (bool, error)
+}
+
+// ResolveClaims разбирает токен и отклоняет токены заблокированных пользователей. Токен имперсонации
+// действует и для заблокированного: через него поддержка разбирается с его аккаунтом.
+func ResolveClaims(ctx context.Context, auth *auth.AuthService, bans BanChecker, tokenString string) (*model.Claims, error) {
+	claims, err := auth.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Act != nil {
+		return claims, nil
+	}
+
+	banned, err := bans.IsUserBanned(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if banned {
+		return nil, ErrUserBanned
+	}
+
+	return claims, nil
+}
+
+func AuthMiddleware(auth *auth.AuthService, bans BanChecker) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			jwt := r.Header.Get("Authorization")
@@ -32,7 +63,7 @@ func AuthMiddleware(auth *auth.AuthService) func(http.Handler) http.Handler {
 				return
 			}
 
-			claims, err := auth.ParseToken(tokenString)
+			claims, err := ResolveClaims(r.Context(), auth, bans, tokenString)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
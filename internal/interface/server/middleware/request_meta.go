@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	chiMiddleware "github.com/go-chi/chi/middleware"
+)
+
+const (
+	RequestMetaKey authKey = "requestMeta"
+)
+
+// RequestMeta - сведения о запросе, которые попадают в журнал аудита.
+type RequestMeta struct {
+	IP        string
+	RequestID string
+	UserAgent string
+}
+
+// RequestMetaMiddleware должен стоять после chi RequestID и RealIPMiddleware,
+// чтобы в контекст попали уже вычисленные идентификатор запроса и адрес клиента.
+func RequestMetaMiddleware(h http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		meta := &RequestMeta{
+			IP:        ip,
+			RequestID: chiMiddleware.GetReqID(r.Context()),
+			UserAgent: r.UserAgent(),
+		}
+
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), RequestMetaKey, meta)))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func GetRequestMeta(ctx context.Context) *RequestMeta {
+	meta, ok := ctx.Value(RequestMetaKey).(*RequestMeta)
+	if !ok || meta == nil {
+		return &RequestMeta{}
+	}
+
+	return meta
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net"
 	"net/http"
 	"time"
 
@@ -43,15 +44,18 @@ func NewServer(logger *zap.Logger) *Server {
 	}
 }
 
-func (s *Server) InitMiddlewares(isDebug bool, auth *auth.AuthService) {
+func (s *Server) InitMiddlewares(isDebug bool, auth *auth.AuthService, bans middleware.BanChecker, trustedProxies []*net.IPNet) {
 	s.router.Use(render.SetContentType(render.ContentTypeJSON))
+	s.router.Use(chiMiddleware.RequestID)
+	s.router.Use(middleware.RealIPMiddleware(trustedProxies))
 	s.router.Use(chiMiddleware.Recoverer)
+	s.router.Use(middleware.RequestMetaMiddleware)
 
 	if isDebug {
 		s.router.Use(chiMiddleware.Logger)
 	}
 
-	s.router.Use(middleware.AuthMiddleware(auth))
+	s.router.Use(middleware.AuthMiddleware(auth, bans))
 
 	options := cors.Options{
 		AllowedMethods:   []string{"GET", "PUT", "POST", "OPTIONS"},
@@ -66,7 +70,7 @@ func (s *Server) InitMiddlewares(isDebug bool, auth *auth.AuthService) {
 	s.router.Use(cors.Handler)
 }
 
-func (s *Server) InitRoutes(container *registry.Container, isDebug bool) {
+func (s *Server) InitRoutes(container *registry.Container, authService *auth.AuthService, isDebug bool) {
 	graphqlHandler := handlers.NewGraphqlHandler(
 		s.logger,
 		gen.NewExecutableSchema(
@@ -76,6 +80,11 @@ func (s *Server) InitRoutes(container *registry.Container, isDebug bool) {
 				),
 			},
 		),
+		container.UseCases.Audit,
+		authService,
+		container.UseCases.Users,
+		// В GraphQL загружаются только аватары, больше их лимита принимать незачем
+		container.UseCases.Users.ImageLimits().MaxBytes+handlers.MultipartOverhead,
 		isDebug,
 	)
 
@@ -84,10 +93,19 @@ func (s *Server) InitRoutes(container *registry.Container, isDebug bool) {
 
 	s.router.Handle("/*", graphqlHandler)
 	s.router.Route(string(handlers.ImageUrl), func(r chi.Router) {
+		r.Use(restHandler.AuditImpersonationMiddleware)
 		r.Put("/", restHandler.PutImageHandler)
 		r.Get("/", restHandler.GetImageHandler)
 		r.Get("/ping", restHandler.PingHandler)
 	})
+	s.router.Route(string(handlers.EmailRevertUrl), func(r chi.Router) {
+		r.Get("/", restHandler.GetEmailRevertHandler)
+		r.Post("/", restHandler.PostEmailRevertHandler)
+	})
+	s.router.Route(string(handlers.UnsubscribeUrl), func(r chi.Router) {
+		r.Get("/", restHandler.GetUnsubscribeHandler)
+		r.Post("/", restHandler.PostUnsubscribeHandler)
+	})
 	s.router.Route(string(handlers.PingUrl), func(r chi.Router) {
 		r.Get("/", restHandler.PingHandler)
 	})
@@ -6,9 +6,13 @@ import (
 )
 
 type UseCases struct {
-	Users  *usecases.User
-	Topics *usecases.Topic
-	Games  *usecases.Game
+	Users         *usecases.User
+	Topics        *usecases.Topic
+	Games         *usecases.Game
+	Reports       *usecases.Report
+	Audit         *usecases.Audit
+	Mail          *usecases.Mail
+	Notifications *usecases.Notification
 }
 
 type Container struct {
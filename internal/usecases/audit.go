@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+	"go.uber.org/zap"
+)
+
+const (
+	maxAuditPageSize = 100
+)
+
+type Audit struct {
+	auditRepo repo.AuditRepository
+	logger    *zap.Logger
+}
+
+func NewAuditUseCase(auditRepo repo.AuditRepository, logger *zap.Logger) *Audit {
+	return &Audit{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// Record сохраняет событие аудита от имени текущего пользователя из JWT.
+// К моменту вызова действие уже выполнено, поэтому ошибка записи не возвращается, а логируется.
+func (a *Audit) Record(
+	ctx context.Context,
+	action model.AuditActionEnum,
+	targetType model.AuditTargetEnum,
+	targetId int,
+	before, after map[string]interface{},
+) {
+	meta := middleware.GetRequestMeta(ctx)
+	event := &model.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   strconv.Itoa(targetId),
+		Before:     before,
+		After:      after,
+		IP:         meta.IP,
+		RequestID:  meta.RequestID,
+	}
+
+	if claims, _ := ctx.Value(middleware.JwtClaimsKey).(*model.Claims); claims != nil {
+		event.ActorID = &claims.UserID
+		event.ActorRole = &claims.Role
+		if claims.IsImpersonated() {
+			event.ImpersonatorID = &claims.Act.UserID
+		}
+	}
+
+	if err := a.auditRepo.CreateEvent(ctx, event); err != nil {
+		a.logger.Error("failed to write audit event",
+			zap.String("action", string(action)),
+			zap.String("targetType", string(targetType)),
+			zap.Int("targetId", targetId),
+			zap.Error(err),
+		)
+	}
+}
+
+// RecordImpersonatedRequest фиксирует любой запрос, сделанный по токену имперсонации.
+// Для обычных токенов ничего не делает.
+func (a *Audit) RecordImpersonatedRequest(ctx context.Context, operation string, details map[string]interface{}) {
+	claims, _ := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil || !claims.IsImpersonated() {
+		return
+	}
+
+	after := map[string]interface{}{
+		"operation": operation,
+		"reason":    claims.Act.Reason,
+	}
+	for k, v := range details {
+		after[k] = v
+	}
+
+	a.Record(ctx, model.AuditActionImpersonatedRequest, model.AuditTargetUser, claims.UserID, nil, after)
+}
+
+// GetEvents - постраничная выдача журнала для администраторов. Курсор - непрозрачная строка,
+// которую нужно передать в следующий запрос, чтобы получить более старые события.
+func (a *Audit) GetEvents(
+	ctx context.Context,
+	filter model.AuditEventFilter,
+	cursor *string,
+	limit int,
+) ([]model.AuditEvent, *string, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil || claims.Role != model.RoleAdmin {
+		return nil, nil, repo.ErrUnauthorized
+	}
+
+	if limit <= 0 || limit > maxAuditPageSize {
+		return nil, nil, repo.ErrValidation
+	}
+
+	var afterId *int
+	if cursor != nil {
+		id, err := decodeCursor(*cursor)
+		if err != nil {
+			return nil, nil, repo.ErrValidation
+		}
+		afterId = &id
+	}
+
+	events, err := a.auditRepo.GetEvents(ctx, filter, afterId, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nextCursor *string
+	if len(events) == limit {
+		next := encodeCursor(events[len(events)-1].ID)
+		nextCursor = &next
+	}
+
+	return events, nextCursor, nil
+}
+
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(string(raw))
+}
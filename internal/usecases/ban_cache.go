@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	banCacheSize = 10000
+	// banCacheTTL — через сколько бан вступает в силу на инстансах, которые его не выносили:
+	// ResolveReport сбрасывает запись только у себя.
+	banCacheTTL = 30 * time.Second
+)
+
+// BanCache хранит недавно проверенные статусы бана, чтобы не ходить в базу на каждый запрос.
+type BanCache struct {
+	lru *expirable.LRU[int, bool]
+}
+
+func NewBanCache() *BanCache {
+	return &BanCache{
+		lru: expirable.NewLRU[int, bool](banCacheSize, nil, banCacheTTL),
+	}
+}
+
+func (c *BanCache) get(userId int) (bool, bool) {
+	return c.lru.Get(userId)
+}
+
+func (c *BanCache) add(userId int, banned bool) {
+	c.lru.Add(userId, banned)
+}
+
+func (c *BanCache) invalidate(userId int) {
+	c.lru.Remove(userId)
+}
@@ -2,39 +2,254 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailtemplate"
 	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+	"go.uber.org/zap"
 )
 
 type Game struct {
-	gameRepo repo.GameRepository
+	gameRepo        repo.GameRepository
+	achievementRepo repo.AchievmentsRepository
+	userRepo        repo.UserRepository
+	notifications   *Notification
+	events          repo.EventBus[model.GameStatus]
+	retention       time.Duration
+	logger          *zap.Logger
 }
 
-func NewGameUseCase(gameRepo repo.GameRepository) *Game {
+func NewGameUseCase(
+	gameRepo repo.GameRepository,
+	achievementRepo repo.AchievmentsRepository,
+	userRepo repo.UserRepository,
+	notifications *Notification,
+	events repo.EventBus[model.GameStatus],
+	retention time.Duration,
+	logger *zap.Logger,
+) *Game {
 	return &Game{
-		gameRepo: gameRepo,
+		gameRepo:        gameRepo,
+		achievementRepo: achievementRepo,
+		userRepo:        userRepo,
+		notifications:   notifications,
+		events:          events,
+		retention:       retention,
+		logger:          logger,
 	}
 }
 
 func (g *Game) StartGame(ctx context.Context, startGameRequest model.StartGame) (model.GameStatus, error) {
+	playerId, err := currentPlayer(ctx, startGameRequest.FromUserID)
+	if err != nil {
+		return model.GameStatus{}, err
+	}
+	startGameRequest.FromUserID = playerId
+
+	if opponentId := startGameRequest.OpponentID; opponentId != 0 {
+		if opponentId == playerId {
+			return model.GameStatus{}, repo.ErrValidation
+		}
+		if _, err := g.userRepo.FindUserByID(ctx, opponentId); err != nil {
+			return model.GameStatus{}, err
+		}
+	}
 
 	game, err := g.gameRepo.StartGame(ctx, startGameRequest)
 	if err != nil {
 		return model.GameStatus{}, err
 	}
 
+	// Вызываем соперника, пока он не пришёл. Повтор запроса отсекается ключом с идентификатором комнаты
+	if startGameRequest.OpponentID != 0 && game.GameStatusEnum == model.GameStatusPending && game.FirstPlayerId == playerId {
+		g.challenge(ctx, game.ID, playerId, startGameRequest.OpponentID)
+	}
+
+	// Второй игрок опоздал, и игра не состоялась
+	if game.GameStatusEnum == model.GameStatusDeclined {
+		g.applyResult(ctx, gameResultOf(game))
+	}
+
 	return game, nil
 }
 
 func (g *Game) FinishGame(ctx context.Context, finishGameRequest model.FinishGame) (model.GameResult, error) {
+	playerId, err := currentPlayer(ctx, finishGameRequest.FromUserID)
+	if err != nil {
+		return model.GameResult{}, err
+	}
+	finishGameRequest.FromUserID = playerId
+
 	gameResult, err := g.gameRepo.FinishGame(ctx, finishGameRequest)
-	if gameResult.WinnerId != 0 { // Салям, Владос, спасибо за сына!
-		g.gameRepo.SetWinnerId(ctx, finishGameRequest.RoomID, gameResult.WinnerId)
+	if err != nil {
+		return model.GameResult{}, err
+	}
+	g.applyResult(ctx, gameResult)
+
+	return gameResult, nil
+}
+
+// JudgeGame выносит итог игры, которую ResultPolicy оставила судье. winnerId == nil — ничья.
+func (g *Game) JudgeGame(ctx context.Context, roomId string, winnerId *int) (model.GameStatus, error) {
+	if !isModerator(ctx) {
+		return model.GameStatus{}, repo.ErrUnauthorized
+	}
+
+	verdict := model.GameVerdict{Outcome: model.GameOutcomeDraw}
+	if winnerId != nil {
+		verdict = model.GameVerdict{WinnerId: *winnerId, Outcome: model.GameOutcomeWin}
+	}
+
+	game, err := g.gameRepo.JudgeGame(ctx, roomId, verdict)
+	if err != nil {
+		return model.GameStatus{}, err
+	}
+	g.applyResult(ctx, gameResultOf(game))
+
+	return game, nil
+}
+
+// ReapGames завершает игры, дедлайн которых прошёл, пока никто не спрашивал их статус, и забывает
+// закончившиеся больше retention назад. Возвращает число завершённых и забытых игр.
+func (g *Game) ReapGames(ctx context.Context) (int, int, error) {
+	now := time.Now().UTC()
+
+	expired, err := g.gameRepo.ExpireGames(ctx, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, game := range expired {
+		g.applyResult(ctx, gameResultOf(game))
+	}
+
+	evicted, err := g.gameRepo.EvictGames(ctx, now.Add(-g.retention))
+	if err != nil {
+		return len(expired), 0, err
+	}
+
+	return len(expired), evicted, nil
+}
+
+// applyResult сообщает итог игрокам и выдаёт победителю ачивки. Сам итог GameRepository уже записал
+// в games вместе с состоянием игры. Итог, который ждёт судью, применится после judgeGame.
+func (g *Game) applyResult(ctx context.Context, result model.GameResult) {
+	if result.AwaitingJudge || result.Outcome == "" {
+		return
+	}
+
+	// Салям, Владос, спасибо за сына!
+	g.notifyFinished(ctx, result)
+	if result.WinnerId != 0 {
+		g.awardAchievements(ctx, result.WinnerId)
+	}
+}
+
+func gameResultOf(game model.GameStatus) model.GameResult {
+	return model.GameResult{
+		RoomID:        game.ID,
+		WinnerId:      game.WinnerId,
+		Outcome:       game.Outcome,
+		AwaitingJudge: game.AwaitingJudge,
+	}
+}
+
+// currentPlayer — игрок берётся из токена. fromUserId из запроса оставлен для старых клиентов
+// и должен совпадать с ним, иначе это попытка сыграть за другого.
+func currentPlayer(ctx context.Context, fromUserId int) (int, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return 0, repo.ErrUnauthorized
+	}
+	if fromUserId != 0 && fromUserId != claims.UserID {
+		return 0, repo.ErrUnauthorized
+	}
+
+	return claims.UserID, nil
+}
+
+// challenge сообщает opponentId, что игрок playerId ждёт его в комнате roomId.
+func (g *Game) challenge(ctx context.Context, roomId string, playerId int, opponentId int) {
+	player, err := g.userRepo.FindUserByID(ctx, playerId)
+	if err != nil {
+		g.logger.Error("failed to load challenger", zap.Int("userId", playerId), zap.Error(err))
+		return
+	}
+
+	g.notifications.Notify(ctx, opponentId, model.NotificationTypeGameChallenge, "challenge:"+roomId,
+		map[string]interface{}{
+			"roomId":       roomId,
+			"fromUserId":   playerId,
+			"fromUsername": player.Username,
+		}, &NotificationEmail{
+			Category: model.NotificationCategoryGameInvites,
+			Template: mailtemplate.MessageGameChallenge,
+			Data:     mailtemplate.GameChallengeData{ChallengerName: player.Username},
+		})
+}
+
+// notifyFinished сообщает обоим игрокам итог игры. Оба игрока присылают завершение,
+// повторные уведомления отсекаются ключом с идентификатором комнаты.
+func (g *Game) notifyFinished(ctx context.Context, result model.GameResult) {
+	game, err := g.gameRepo.GetGameById(ctx, result.RoomID)
+	if err != nil {
+		return
+	}
+
+	players := [][2]int{
+		{game.FirstPlayerId, game.SecondPlayerId},
+		{game.SecondPlayerId, game.FirstPlayerId},
+	}
+	for _, pair := range players {
+		if pair[0] == 0 {
+			continue
+		}
+
+		g.notifications.Notify(ctx, pair[0], model.NotificationTypeGameFinished, "game:"+result.RoomID,
+			map[string]interface{}{
+				"roomId":     result.RoomID,
+				"opponentId": pair[1],
+				"won":        pair[0] == result.WinnerId,
+				"outcome":    result.Outcome,
+			}, &NotificationEmail{
+				Category: model.NotificationCategoryDigest,
+				Template: mailtemplate.MessageGameFinished,
+				Data: mailtemplate.GameFinishedData{
+					Outcome: string(result.Outcome),
+					Won:     pair[0] == result.WinnerId,
+				},
+			})
+	}
+}
+
+// awardAchievements выдаёт победителю ачивки за число побед и сообщает о каждой новой.
+func (g *Game) awardAchievements(ctx context.Context, userId int) {
+	awarded, err := g.achievementRepo.AwardWinAchievements(ctx, userId)
+	if err != nil {
+		g.logger.Error("failed to award achievements", zap.Int("userId", userId), zap.Error(err))
+		return
 	}
 
-	return gameResult, err
+	for _, achievement := range awarded {
+		g.notifications.Notify(ctx, userId, model.NotificationTypeAchievementUnlocked,
+			fmt.Sprintf("achievement:%d", achievement.ID),
+			map[string]interface{}{
+				"achievementId": achievement.ID,
+				"name":          achievement.Name,
+				"description":   achievement.Description,
+			}, &NotificationEmail{
+				Category: model.NotificationCategoryAchievements,
+				Template: mailtemplate.MessageAchievementUnlocked,
+				Data: mailtemplate.AchievementUnlockedData{
+					Name:        achievement.Name,
+					Description: achievement.Description,
+				},
+			})
+	}
 }
 
 func (g *Game) GetGameStatus(ctx context.Context, gameID string) (model.GameStatus, error) {
@@ -50,7 +265,13 @@ func (g *Game) GetGameStatus(ctx context.Context, gameID string) (model.GameStat
 
 	// Завершение игры по дедлайну ожидания подтверждения начала игры от второго игрока
 	if g.gameRepo.IsGameOverByDeadline(ctx, gameID) {
-		return g.gameRepo.FinishGameByDeadline(ctx, claims.UserID, game)
+		game, err = g.gameRepo.FinishGameByDeadline(ctx, gameID)
+		if err != nil {
+			return model.GameStatus{}, err
+		}
+		g.applyResult(ctx, gameResultOf(game))
+
+		return game, nil
 	}
 
 	// Ретрай от первого игрока
@@ -60,3 +281,60 @@ func (g *Game) GetGameStatus(ctx context.Context, gameID string) (model.GameStat
 
 	return game, nil
 }
+
+// SubscribeGameStatus — изменения статуса игры в комнате, пока жив ctx. Первым приходит текущий
+// статус, если игра уже началась, чтобы клиент не пропустил изменения до подписки.
+// Следить за игрой могут только её игроки и модераторы.
+func (g *Game) SubscribeGameStatus(ctx context.Context, roomId string) (<-chan model.GameStatus, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return nil, repo.ErrUnauthorized
+	}
+
+	canWatch := func(game *model.GameStatus) bool {
+		return game.HasPlayer(claims.UserID) || isModerator(ctx)
+	}
+
+	events := g.events.Subscribe(ctx, roomId)
+
+	// В комнату, где ещё никого нет, можно подписаться заранее
+	game, err := g.gameRepo.GetGameById(ctx, roomId)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return nil, err
+	}
+	if game.ID != "" && !canWatch(&game) {
+		return nil, repo.ErrUnauthorized
+	}
+
+	output := make(chan model.GameStatus, 1)
+	go func() {
+		defer close(output)
+
+		checked := game.ID != ""
+		if checked {
+			select {
+			case output <- game:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for event := range events {
+			// Подписавшийся заранее должен оказаться среди игроков комнаты, когда она появится
+			if !checked {
+				if !canWatch(&event) {
+					return
+				}
+				checked = true
+			}
+
+			select {
+			case output <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return output, nil
+}
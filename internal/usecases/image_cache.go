@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// imageCacheTTL ограничивает время жизни записи: при нескольких инстансах
+// загрузка аватара сбрасывает кеш только на том, который её обработал.
+const imageCacheTTL = time.Minute
+
+type imageCacheKey struct {
+	userId int
+	size   int
+	webp   bool
+}
+
+// ImageCache хранит недавно отданные аватары в памяти процесса.
+type ImageCache struct {
+	lru *expirable.LRU[imageCacheKey, *model.ImageContent]
+}
+
+// NewImageCache создаёт кеш на size записей. При size <= 0 кеширование отключено.
+func NewImageCache(size int) *ImageCache {
+	if size <= 0 {
+		return &ImageCache{}
+	}
+
+	return &ImageCache{
+		lru: expirable.NewLRU[imageCacheKey, *model.ImageContent](size, nil, imageCacheTTL),
+	}
+}
+
+func (c *ImageCache) get(key imageCacheKey) (*model.ImageContent, bool) {
+	if c.lru == nil {
+		return nil, false
+	}
+
+	return c.lru.Get(key)
+}
+
+func (c *ImageCache) add(key imageCacheKey, image *model.ImageContent) {
+	if c.lru == nil {
+		return
+	}
+
+	c.lru.Add(key, image)
+}
+
+// invalidate удаляет все закешированные варианты аватара пользователя.
+func (c *ImageCache) invalidate(userId int) {
+	if c.lru == nil {
+		return
+	}
+
+	for _, key := range c.lru.Keys() {
+		if key.userId == userId {
+			c.lru.Remove(key)
+		}
+	}
+}
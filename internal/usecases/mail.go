@@ -0,0 +1,235 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailtemplate"
+	"github.com/debate-io/service-auth/internal/infrastructure/unsubscribe"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+)
+
+const (
+	emailBatchSize = 20
+	// emailLease — на сколько откладывается взятое воркером письмо. Если воркер упал посреди отправки,
+	// письмо снова станет доступным по истечении этого времени.
+	emailLease = 5 * time.Minute
+
+	emailRetryBaseDelay = 30 * time.Second
+	emailRetryMaxDelay  = 6 * time.Hour
+
+	maxOutboxPageSize = 100
+)
+
+// UnsubscribePath — адрес отписки. По нему строится ссылка в письме и регистрируется маршрут.
+const UnsubscribePath = "/user/notifications/unsubscribe"
+
+type Mail struct {
+	outboxRepo     repo.EmailOutboxRepository
+	preferenceRepo repo.NotificationPreferenceRepository
+	mailer         repo.Mailer
+	renderer       *mailtemplate.Renderer
+	unsubscribe    *unsubscribe.Signer
+	publicBaseUrl  string
+	maxAttempts    int
+	retention      time.Duration
+}
+
+func NewMailUseCase(
+	outboxRepo repo.EmailOutboxRepository,
+	preferenceRepo repo.NotificationPreferenceRepository,
+	mailer repo.Mailer,
+	renderer *mailtemplate.Renderer,
+	unsubscribe *unsubscribe.Signer,
+	publicBaseUrl string,
+	maxAttempts int,
+	retention time.Duration,
+) *Mail {
+	return &Mail{
+		outboxRepo:     outboxRepo,
+		preferenceRepo: preferenceRepo,
+		mailer:         mailer,
+		renderer:       renderer,
+		unsubscribe:    unsubscribe,
+		publicBaseUrl:  publicBaseUrl,
+		maxAttempts:    maxAttempts,
+		retention:      retention,
+	}
+}
+
+func (m *Mail) DefaultLocale() model.LocaleEnum {
+	return model.LocaleEnum(m.renderer.DefaultLocale())
+}
+
+// ComposeEmail собирает письмо пользователю user на адрес to на его языке. Если пользователь
+// отписался от категории, возвращает nil: такое письмо не должно попасть в очередь.
+// Сохраняет письмо вызывающий, в одной транзакции с изменениями, к которым оно относится.
+func (m *Mail) ComposeEmail(
+	ctx context.Context,
+	user *model.User,
+	to string,
+	category model.NotificationCategoryEnum,
+	name string,
+	data interface{},
+) (*model.OutboxEmail, error) {
+	var unsubscribeLink string
+	if category.Optional() {
+		enabled, err := m.isEnabled(ctx, user.ID, category)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, nil
+		}
+
+		unsubscribeLink = m.unsubscribeLink(user.ID, category)
+	}
+
+	message, err := m.renderer.Render(name, string(user.Locale), unsubscribeLink, data)
+	if err != nil {
+		return nil, err
+	}
+
+	email := &model.OutboxEmail{
+		Recipient: to,
+		Category:  category,
+		Subject:   message.Subject,
+		TextBody:  message.Text,
+		HtmlBody:  message.HTML,
+	}
+	if unsubscribeLink != "" {
+		email.ListUnsubscribe = &unsubscribeLink
+	}
+
+	return email, nil
+}
+
+// EnqueueEmail собирает письмо пользователю на его почту и ставит в очередь. Если пользователь
+// отписался от категории, ничего не делает.
+func (m *Mail) EnqueueEmail(
+	ctx context.Context,
+	user *model.User,
+	category model.NotificationCategoryEnum,
+	name string,
+	data interface{},
+) error {
+	email, err := m.ComposeEmail(ctx, user, user.Email, category, name, data)
+	if err != nil || email == nil {
+		return err
+	}
+
+	return m.outboxRepo.EnqueueEmail(ctx, email)
+}
+
+// DeliverPendingEmails отправляет очередную пачку писем из очереди. Неудачная отправка откладывается
+// с экспоненциальной задержкой, после maxAttempts попыток письмо помечается как DEAD.
+func (m *Mail) DeliverPendingEmails(ctx context.Context) (sent int, dead int, err error) {
+	emails, err := m.outboxRepo.ClaimDueEmails(ctx, emailBatchSize, emailLease)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, email := range emails {
+		if ctx.Err() != nil {
+			return sent, dead, nil
+		}
+
+		// Код или ссылка из письма уже не действуют, отправлять его незачем
+		if email.IsExpired(time.Now()) {
+			lastError := "expired before delivery"
+			email.LastError = &lastError
+			email.Status = model.EmailStatusDead
+			if err := m.outboxRepo.MarkEmailFailed(ctx, email); err != nil {
+				return sent, dead, err
+			}
+			dead++
+			continue
+		}
+
+		message := &model.MailMessage{
+			To:      email.Recipient,
+			Subject: email.Subject,
+			Text:    email.TextBody,
+			HTML:    email.HtmlBody,
+		}
+		if email.ListUnsubscribe != nil {
+			message.Unsubscribe = *email.ListUnsubscribe
+		}
+
+		sendErr := m.mailer.Send(ctx, message)
+		if sendErr == nil {
+			if err := m.outboxRepo.MarkEmailSent(ctx, email.ID); err != nil {
+				return sent, dead, err
+			}
+			sent++
+			continue
+		}
+
+		lastError := sendErr.Error()
+		email.LastError = &lastError
+		email.Attempts++
+		if email.Attempts >= m.maxAttempts {
+			email.Status = model.EmailStatusDead
+			dead++
+		} else {
+			email.NextAttemptAt = time.Now().Add(emailRetryDelay(email.Attempts))
+		}
+
+		if err := m.outboxRepo.MarkEmailFailed(ctx, email); err != nil {
+			return sent, dead, err
+		}
+	}
+
+	return sent, dead, nil
+}
+
+func (m *Mail) GetOutboxEmails(
+	ctx context.Context,
+	statuses []model.EmailStatusEnum,
+	limit int,
+	offset int,
+) ([]*model.OutboxEmail, error) {
+	if !isAdmin(ctx) {
+		return nil, repo.ErrUnauthorized
+	}
+	if limit <= 0 || limit > maxOutboxPageSize || offset < 0 {
+		return nil, repo.ErrValidation
+	}
+
+	return m.outboxRepo.GetEmails(ctx, statuses, limit, offset)
+}
+
+// RetryOutboxEmail возвращает в очередь письмо, которое не удалось доставить. Письмо с истёкшим кодом
+// или ссылкой не возвращается: пользователь получил бы недействительный код.
+func (m *Mail) RetryOutboxEmail(ctx context.Context, id int) (*model.OutboxEmail, error) {
+	if !isAdmin(ctx) {
+		return nil, repo.ErrUnauthorized
+	}
+	if id <= 0 {
+		return nil, repo.ErrValidation
+	}
+
+	return m.outboxRepo.RetryEmail(ctx, id)
+}
+
+// RedactDeliveredEmails стирает тела писем старше retention: в них коды и ссылки, которые незачем
+// хранить после доставки. Возвращает число стёртых писем.
+func (m *Mail) RedactDeliveredEmails(ctx context.Context) (int, error) {
+	return m.outboxRepo.RedactEmails(ctx, time.Now().Add(-m.retention))
+}
+
+func emailRetryDelay(attempts int) time.Duration {
+	delay := emailRetryBaseDelay
+	for i := 1; i < attempts && delay < emailRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, emailRetryMaxDelay)
+}
+
+func isAdmin(ctx context.Context) bool {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	return claims != nil && claims.Role == model.RoleAdmin
+}
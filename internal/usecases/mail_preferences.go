@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+)
+
+// GetNotificationPreferences возвращает настройки текущего пользователя по всем категориям.
+func (m *Mail) GetNotificationPreferences(ctx context.Context) ([]model.NotificationPreference, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return nil, repo.ErrUnauthorized
+	}
+
+	return m.preferences(ctx, claims.UserID)
+}
+
+// UpdateNotificationPreferences меняет подписки текущего пользователя. Категорию SECURITY отключить нельзя.
+func (m *Mail) UpdateNotificationPreferences(
+	ctx context.Context,
+	preferences []model.NotificationPreference,
+) ([]model.NotificationPreference, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return nil, repo.ErrUnauthorized
+	}
+
+	// Одна категория может прийти несколько раз, побеждает последнее значение
+	byCategory := make(map[model.NotificationCategoryEnum]int, len(preferences))
+	deduped := make([]model.NotificationPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		if !preference.Category.Valid() || !preference.Category.Optional() {
+			return nil, repo.ErrValidation
+		}
+		preference.UserID = claims.UserID
+
+		if i, ok := byCategory[preference.Category]; ok {
+			deduped[i] = preference
+			continue
+		}
+		byCategory[preference.Category] = len(deduped)
+		deduped = append(deduped, preference)
+	}
+
+	if err := m.preferenceRepo.SetNotificationPreferences(ctx, deduped); err != nil {
+		return nil, err
+	}
+
+	return m.preferences(ctx, claims.UserID)
+}
+
+// Unsubscribe отключает категорию по токену из ссылки в письме. Авторизация не требуется:
+// токен подписан и выдаётся только владельцу ящика.
+func (m *Mail) Unsubscribe(ctx context.Context, token string) error {
+	userId, rawCategory, err := m.unsubscribe.Parse(token)
+	if err != nil {
+		return repo.ErrValidation
+	}
+
+	category := model.NotificationCategoryEnum(rawCategory)
+	if !category.Valid() || !category.Optional() {
+		return repo.ErrValidation
+	}
+
+	return m.preferenceRepo.SetNotificationPreferences(ctx, []model.NotificationPreference{{
+		UserID:   userId,
+		Category: category,
+		Enabled:  false,
+	}})
+}
+
+// preferences дополняет явно заданные настройки значениями по умолчанию: все категории включены.
+func (m *Mail) preferences(ctx context.Context, userId int) ([]model.NotificationPreference, error) {
+	stored, err := m.preferenceRepo.GetNotificationPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[model.NotificationCategoryEnum]bool, len(stored))
+	for _, preference := range stored {
+		enabled[preference.Category] = preference.Enabled
+	}
+
+	output := make([]model.NotificationPreference, 0, len(model.NotificationCategories))
+	for _, category := range model.NotificationCategories {
+		preference := model.NotificationPreference{UserID: userId, Category: category, Enabled: true}
+		if value, ok := enabled[category]; ok && category.Optional() {
+			preference.Enabled = value
+		}
+		output = append(output, preference)
+	}
+
+	return output, nil
+}
+
+func (m *Mail) isEnabled(ctx context.Context, userId int, category model.NotificationCategoryEnum) (bool, error) {
+	preferences, err := m.preferences(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, preference := range preferences {
+		if preference.Category == category {
+			return preference.Enabled, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (m *Mail) unsubscribeLink(userId int, category model.NotificationCategoryEnum) string {
+	token := m.unsubscribe.Token(userId, string(category))
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimSuffix(m.publicBaseUrl, "/"), UnsubscribePath, url.QueryEscape(token))
+}
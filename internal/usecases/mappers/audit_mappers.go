@@ -0,0 +1,57 @@
+package mappers
+
+import (
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+)
+
+func MapGetAuditEventsInputToFilter(input *gen.GetAuditEventsInput) model.AuditEventFilter {
+	filter := model.AuditEventFilter{
+		ActorID:        input.ActorID,
+		ImpersonatorID: input.ImpersonatorID,
+		TargetID:       input.TargetID,
+		From:           input.From,
+		To:             input.To,
+	}
+	if input.Action != nil {
+		action := model.AuditActionEnum(*input.Action)
+		filter.Action = &action
+	}
+	if input.TargetType != nil {
+		targetType := model.AuditTargetEnum(*input.TargetType)
+		filter.TargetType = &targetType
+	}
+
+	return filter
+}
+
+func MapAuditEventToDTO(event *model.AuditEvent) *gen.AuditEvent {
+	var actorRole *gen.Role
+	if event.ActorRole != nil {
+		role := gen.Role(*event.ActorRole)
+		actorRole = &role
+	}
+
+	return &gen.AuditEvent{
+		ID:             event.ID,
+		ActorID:        event.ActorID,
+		ActorRole:      actorRole,
+		ImpersonatorID: event.ImpersonatorID,
+		Action:         gen.AuditAction(event.Action),
+		TargetType:     gen.AuditTarget(event.TargetType),
+		TargetID:       event.TargetID,
+		Before:         event.Before,
+		After:          event.After,
+		IP:             event.IP,
+		RequestID:      event.RequestID,
+		CreatedAt:      event.CreatedAt,
+	}
+}
+
+func MapAuditEventsToDTO(events []model.AuditEvent) []*gen.AuditEvent {
+	output := make([]*gen.AuditEvent, 0, len(events))
+	for i := range events {
+		output = append(output, MapAuditEventToDTO(&events[i]))
+	}
+	return output
+}
@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+)
+
+func MapGameStatusToDTO(gameStatus *model.GameStatus) *gen.GameStatus {
+	return &gen.GameStatus{
+		RoomID:   gameStatus.ID,
+		Status:   string(gameStatus.GameStatusEnum),
+		WinnerID: &gameStatus.WinnerId,
+		StartAt:  gameStatus.StartAt,
+		FinishAt: gameStatus.FinishAt,
+
+		Outcome:       MapGameOutcomeToDTO(gameStatus.Outcome),
+		AwaitingJudge: gameStatus.AwaitingJudge,
+
+		CreatedAt:  gameStatus.CreatedAt,
+		StartedAt:  gameStatus.StartedAt,
+		DeclinedAt: gameStatus.DeclinedAt,
+		FinishedAt: gameStatus.FinishedAt,
+	}
+}
+
+func MapGameOutcomeToDTO(outcome model.GameOutcomeEnum) *gen.GameOutcome {
+	if outcome == "" {
+		return nil
+	}
+
+	dto := gen.GameOutcome(outcome)
+	return &dto
+}
@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+)
+
+func MapEmailStatusesFromDTO(statuses []gen.EmailStatus) []model.EmailStatusEnum {
+	output := make([]model.EmailStatusEnum, 0, len(statuses))
+	for _, status := range statuses {
+		output = append(output, model.EmailStatusEnum(status))
+	}
+
+	return output
+}
+
+func MapOutboxEmailToDTO(email *model.OutboxEmail) *gen.OutboxEmail {
+	return &gen.OutboxEmail{
+		ID:            email.ID,
+		Recipient:     email.Recipient,
+		Category:      gen.NotificationCategory(email.Category),
+		Subject:       email.Subject,
+		Status:        gen.EmailStatus(email.Status),
+		Attempts:      email.Attempts,
+		LastError:     email.LastError,
+		NextAttemptAt: email.NextAttemptAt,
+		CreatedAt:     email.CreatedAt,
+		SentAt:        email.SentAt,
+	}
+}
+
+func MapOutboxEmailsToDTO(emails []*model.OutboxEmail) []*gen.OutboxEmail {
+	output := make([]*gen.OutboxEmail, 0, len(emails))
+	for _, email := range emails {
+		output = append(output, MapOutboxEmailToDTO(email))
+	}
+
+	return output
+}
+
+func MapNotificationPreferencesToDTO(preferences []model.NotificationPreference) []*gen.NotificationPreference {
+	output := make([]*gen.NotificationPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		output = append(output, &gen.NotificationPreference{
+			Category: gen.NotificationCategory(preference.Category),
+			Enabled:  preference.Enabled,
+		})
+	}
+
+	return output
+}
+
+func MapNotificationPreferencesFromDTO(preferences []*gen.NotificationPreferenceInput) []model.NotificationPreference {
+	output := make([]model.NotificationPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		output = append(output, model.NotificationPreference{
+			Category: model.NotificationCategoryEnum(preference.Category),
+			Enabled:  preference.Enabled,
+		})
+	}
+
+	return output
+}
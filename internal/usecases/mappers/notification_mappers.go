@@ -0,0 +1,25 @@
+package mappers
+
+import (
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+)
+
+func MapNotificationToDTO(notification *model.Notification) *gen.Notification {
+	return &gen.Notification{
+		ID:        notification.ID,
+		Type:      gen.NotificationType(notification.Type),
+		Payload:   notification.Payload,
+		Read:      notification.ReadAt != nil,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func MapNotificationsToDTO(notifications []model.Notification) []*gen.Notification {
+	output := make([]*gen.Notification, 0, len(notifications))
+	for i := range notifications {
+		output = append(output, MapNotificationToDTO(&notifications[i]))
+	}
+
+	return output
+}
@@ -0,0 +1,84 @@
+package mappers
+
+import (
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+)
+
+func MapReportUserInputToReport(input *gen.ReportUserInput) *model.Report {
+	report := &model.Report{
+		ReportedUserID: input.UserID,
+		GameRoomID:     input.GameRoomID,
+		Reason:         model.ReportReasonEnum(input.Reason),
+	}
+	if input.Comment != nil {
+		report.Comment = *input.Comment
+	}
+
+	return report
+}
+
+func MapReportStatusesToReportStatusEnum(statuses ...gen.ReportStatus) (output []model.ReportStatusEnum) {
+	for _, status := range statuses {
+		output = append(output, model.ReportStatusEnum(status))
+	}
+	return
+}
+
+func MapReportDetailsToReportDTO(details *model.ReportDetails) *gen.Report {
+	report := details.Report
+
+	var resolution *gen.ModerationAction
+	if report.Resolution != nil {
+		action := gen.ModerationAction(*report.Resolution)
+		resolution = &action
+	}
+
+	actions := make([]*gen.ModerationActionRecord, 0, len(details.Actions))
+	for i := range details.Actions {
+		actions = append(actions, MapModerationActionToDTO(&details.Actions[i]))
+	}
+
+	return &gen.Report{
+		ID:             report.ID,
+		ReporterID:     report.ReporterID,
+		ReportedUserID: report.ReportedUserID,
+		GameRoomID:     report.GameRoomID,
+		Reason:         gen.ReportReason(report.Reason),
+		Comment:        report.Comment,
+		Status:         gen.ReportStatus(report.Status),
+		Resolution:     resolution,
+		CreatedAt:      report.CreatedAt,
+		ResolvedAt:     report.ResolvedAt,
+		Actions:        actions,
+		OffenderStats:  MapOffenderStatsToDTO(&details.OffenderStats),
+	}
+}
+
+func MapReportsDetailsToReportsDTO(reports []model.ReportDetails) []*gen.Report {
+	output := make([]*gen.Report, 0, len(reports))
+	for i := range reports {
+		output = append(output, MapReportDetailsToReportDTO(&reports[i]))
+	}
+	return output
+}
+
+func MapModerationActionToDTO(action *model.ModerationAction) *gen.ModerationActionRecord {
+	return &gen.ModerationActionRecord{
+		ID:          action.ID,
+		ReportID:    action.ReportID,
+		UserID:      action.UserID,
+		ModeratorID: action.ModeratorID,
+		Action:      gen.ModerationAction(action.Action),
+		Comment:     action.Comment,
+		CreatedAt:   action.CreatedAt,
+	}
+}
+
+func MapOffenderStatsToDTO(stats *model.OffenderStats) *gen.OffenderStats {
+	return &gen.OffenderStats{
+		ReportsCount:  stats.ReportsCount,
+		WarningsCount: stats.WarningsCount,
+		BansCount:     stats.BansCount,
+	}
+}
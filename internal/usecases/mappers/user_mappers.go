@@ -1,18 +1,13 @@
 package mappers
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
 )
 
-func MapUserToDTO(user *model.User) *gen.User {
-	url := "https://encrypted-tbn0.gstatic.com/images?q=tbn:ANd9GcQlwq2pr4enZ_frUAdm0vcxieKI3E1ZYxA-8Q&s"
-	if user.Image != nil {
-		url = fmt.Sprintf("http://185.84.163.166:9090/user/%d/image/", user.ID)
-	}
-
+func MapUserToDTO(user *model.User, imageUrl string) *gen.User {
 	return &gen.User{
 		ID:        int(user.ID),
 		Role:      gen.Role(user.Role),
@@ -20,15 +15,28 @@ func MapUserToDTO(user *model.User) *gen.User {
 		Email:     user.Email,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
-		ImageURL:  url,
+		ImageURL:  imageUrl,
+		Locale:    gen.Locale(strings.ToUpper(string(user.Locale))),
 	}
 }
 
-func MapUsersToDTO(users []*model.User) []*gen.User {
+func MapLocaleFromDTO(locale gen.Locale) model.LocaleEnum {
+	return model.LocaleEnum(strings.ToLower(string(locale)))
+}
+
+func MapUsersToDTO(users []*model.User, imageUrl func(user *model.User) string) []*gen.User {
 	var genUsers []*gen.User
 	for i := range users {
-		genUsers = append(genUsers, MapUserToDTO(users[i]))
+		genUsers = append(genUsers, MapUserToDTO(users[i], imageUrl(users[i])))
 	}
 
 	return genUsers
 }
+
+func MapPasswordViolationsToDTO(violations []model.PasswordViolationEnum) []gen.PasswordViolation {
+	output := make([]gen.PasswordViolation, 0, len(violations))
+	for _, violation := range violations {
+		output = append(output, gen.PasswordViolation(violation))
+	}
+	return output
+}
@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+	"go.uber.org/zap"
+)
+
+const (
+	maxNotificationPageSize = 100
+)
+
+type Notification struct {
+	notificationRepo repo.NotificationRepository
+	userRepo         repo.UserRepository
+	mail             *Mail
+	events           repo.EventBus[model.Notification]
+	logger           *zap.Logger
+}
+
+// NotificationEmail — письмо, которым дублируется новое уведомление.
+type NotificationEmail struct {
+	Category model.NotificationCategoryEnum
+	Template string
+	Data     interface{}
+}
+
+func NewNotificationUseCase(
+	notificationRepo repo.NotificationRepository,
+	userRepo repo.UserRepository,
+	mail *Mail,
+	events repo.EventBus[model.Notification],
+	logger *zap.Logger,
+) *Notification {
+	return &Notification{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		mail:             mail,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+// Notify создаёт уведомление пользователю. Событие, о котором оно сообщает, к этому моменту уже
+// произошло, поэтому ошибка не возвращается, а логируется. Повтор с тем же dedupKey игнорируется.
+// Непустое email отправляется вместе с новым уведомлением, если пользователь не отписался от его категории.
+func (n *Notification) Notify(
+	ctx context.Context,
+	userId int,
+	notificationType model.NotificationTypeEnum,
+	dedupKey string,
+	payload map[string]interface{},
+	email *NotificationEmail,
+) {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+
+	notification := &model.Notification{
+		UserID:   userId,
+		Type:     notificationType,
+		Payload:  payload,
+		DedupKey: dedupKey,
+	}
+	err := n.notificationRepo.CreateNotification(ctx, notification)
+	if err != nil {
+		n.logger.Error("failed to create notification",
+			zap.Int("userId", userId),
+			zap.String("type", string(notificationType)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	// Повтор по dedupKey не вставляется и остаётся без идентификатора
+	if notification.ID == 0 {
+		return
+	}
+
+	n.events.Publish(notificationTopic(userId), *notification)
+	if email != nil {
+		n.sendEmail(ctx, userId, notificationType, email)
+	}
+}
+
+func (n *Notification) sendEmail(
+	ctx context.Context,
+	userId int,
+	notificationType model.NotificationTypeEnum,
+	email *NotificationEmail,
+) {
+	user, err := n.userRepo.FindUserByID(ctx, userId)
+	if err == nil {
+		err = n.mail.EnqueueEmail(ctx, user, email.Category, email.Template, email.Data)
+	}
+	if err != nil {
+		n.logger.Error("failed to enqueue notification email",
+			zap.Int("userId", userId),
+			zap.String("type", string(notificationType)),
+			zap.Error(err),
+		)
+	}
+}
+
+// SubscribeNotifications — новые уведомления текущего пользователя, пока жив ctx.
+func (n *Notification) SubscribeNotifications(ctx context.Context) (<-chan model.Notification, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return nil, repo.ErrUnauthorized
+	}
+
+	return n.events.Subscribe(ctx, notificationTopic(claims.UserID)), nil
+}
+
+// GetMyNotifications — уведомления текущего пользователя от новых к старым. Курсор из nextCursor
+// передаётся в следующий запрос, чтобы получить более старые.
+func (n *Notification) GetMyNotifications(
+	ctx context.Context,
+	cursor *string,
+	limit int,
+	unreadOnly bool,
+) ([]model.Notification, *string, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return nil, nil, repo.ErrUnauthorized
+	}
+
+	if limit <= 0 || limit > maxNotificationPageSize {
+		return nil, nil, repo.ErrValidation
+	}
+
+	var afterId *int
+	if cursor != nil {
+		id, err := decodeCursor(*cursor)
+		if err != nil {
+			return nil, nil, repo.ErrValidation
+		}
+		afterId = &id
+	}
+
+	notifications, err := n.notificationRepo.GetNotifications(ctx, claims.UserID, afterId, limit, unreadOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nextCursor *string
+	if len(notifications) == limit {
+		next := encodeCursor(notifications[len(notifications)-1].ID)
+		nextCursor = &next
+	}
+
+	return notifications, nextCursor, nil
+}
+
+func (n *Notification) UnreadNotificationCount(ctx context.Context) (int, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return 0, repo.ErrUnauthorized
+	}
+
+	return n.notificationRepo.CountUnreadNotifications(ctx, claims.UserID)
+}
+
+// MarkNotificationsRead отмечает прочитанными уведомления ids текущего пользователя, при пустом ids — все.
+// Возвращает оставшееся число непрочитанных.
+func (n *Notification) MarkNotificationsRead(ctx context.Context, ids []int) (int, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return 0, repo.ErrUnauthorized
+	}
+
+	if err := n.notificationRepo.MarkNotificationsRead(ctx, claims.UserID, ids); err != nil {
+		return 0, err
+	}
+
+	return n.notificationRepo.CountUnreadNotifications(ctx, claims.UserID)
+}
+
+func notificationTopic(userId int) string {
+	return strconv.Itoa(userId)
+}
+
+func topicNotificationKey(topicId int, status model.ApprovingStatusEnum) string {
+	return fmt.Sprintf("topic:%d:%s", topicId, status)
+}
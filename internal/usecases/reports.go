@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+)
+
+const (
+	maxReportCommentLength = 1000
+)
+
+type Report struct {
+	reportRepo repo.ReportRepository
+	gameRepo   repo.GameRepository
+	bans       *BanCache
+	audit      *Audit
+}
+
+func NewReportUseCase(reportRepo repo.ReportRepository, gameRepo repo.GameRepository, bans *BanCache, audit *Audit) *Report {
+	return &Report{
+		reportRepo: reportRepo,
+		gameRepo:   gameRepo,
+		bans:       bans,
+		audit:      audit,
+	}
+}
+
+func (r *Report) ReportUser(
+	ctx context.Context,
+	input *model.Report,
+) (*model.ReportDetails, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return nil, repo.ErrUnauthorized
+	}
+
+	input.Comment = strings.TrimSpace(input.Comment)
+	if input.ReportedUserID == claims.UserID || input.GameRoomID == "" || len(input.Comment) > maxReportCommentLength {
+		return nil, repo.ErrValidation
+	}
+
+	// Пожаловаться можно только на соперника из своей игры, в том числе давно закончившейся
+	game, err := r.gameRepo.GetPlayedGame(ctx, input.GameRoomID)
+	if err != nil {
+		return nil, err
+	}
+	if !game.HasPlayer(claims.UserID) || !game.HasPlayer(input.ReportedUserID) {
+		return nil, repo.ErrValidation
+	}
+
+	input.ReporterID = claims.UserID
+	input.Status = model.ReportStatusOpen
+
+	report, err := r.reportRepo.CreateReport(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.reportRepo.GetReport(ctx, report.ID)
+}
+
+func (r *Report) ListReports(
+	ctx context.Context,
+	statuses []model.ReportStatusEnum,
+	reportedUserId *int,
+	pageSize, pageNumber int,
+) ([]model.ReportDetails, int, error) {
+	if !isModerator(ctx) {
+		return nil, 0, repo.ErrUnauthorized
+	}
+
+	if len(statuses) == 0 || pageSize <= 0 || pageNumber < 0 {
+		return nil, 0, repo.ErrValidation
+	}
+
+	return r.reportRepo.GetReports(ctx, statuses, reportedUserId, pageSize, pageNumber)
+}
+
+func (r *Report) ResolveReport(
+	ctx context.Context,
+	reportId int,
+	action model.ModerationActionEnum,
+	comment string,
+) (*model.ReportDetails, error) {
+	if !isModerator(ctx) {
+		return nil, repo.ErrUnauthorized
+	}
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+
+	comment = strings.TrimSpace(comment)
+	if reportId <= 0 || len(comment) > maxReportCommentLength {
+		return nil, repo.ErrValidation
+	}
+
+	report, err := r.reportRepo.ResolveReport(ctx, reportId, &model.ModerationAction{
+		ModeratorID: claims.UserID,
+		Action:      action,
+		Comment:     comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if action == model.ModerationActionBan {
+		r.bans.invalidate(report.Report.ReportedUserID)
+	}
+
+	r.audit.Record(ctx, model.AuditActionReportResolved, model.AuditTargetReport, report.Report.ID,
+		map[string]interface{}{"status": model.ReportStatusOpen},
+		map[string]interface{}{
+			"status":         report.Report.Status,
+			"action":         action,
+			"reportedUserId": report.Report.ReportedUserID,
+			"comment":        comment,
+		},
+	)
+
+	return report, nil
+}
+
+func (r *Report) GetModerationHistory(
+	ctx context.Context,
+	userId int,
+	limit, offset int,
+) ([]model.ModerationAction, *model.OffenderStats, error) {
+	if !isModerator(ctx) {
+		return nil, nil, repo.ErrUnauthorized
+	}
+
+	if userId <= 0 || limit <= 0 || offset < 0 {
+		return nil, nil, repo.ErrValidation
+	}
+
+	actions, err := r.reportRepo.GetModerationActions(ctx, userId, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := r.reportRepo.GetOffenderStats(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return actions, stats, nil
+}
+
+func isModerator(ctx context.Context) bool {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	return claims != nil && (claims.Role == model.RoleAdmin || claims.Role == model.RoleContentManager)
+}
@@ -5,16 +5,21 @@ import (
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailtemplate"
 	"github.com/debate-io/service-auth/internal/interface/server/middleware"
 )
 
 type Topic struct {
-	topicRepo repo.TopicRepository
+	topicRepo     repo.TopicRepository
+	audit         *Audit
+	notifications *Notification
 }
 
-func NewTopicUseCase(topicRepo repo.TopicRepository) *Topic {
+func NewTopicUseCase(topicRepo repo.TopicRepository, audit *Audit, notifications *Notification) *Topic {
 	return &Topic{
-		topicRepo: topicRepo,
+		topicRepo:     topicRepo,
+		audit:         audit,
+		notifications: notifications,
 	}
 }
 
@@ -32,6 +37,7 @@ func (t *Topic) SuggestTopic(
 		return nil, repo.ErrUnauthorized
 	}
 
+	input.SuggestedBy = &claims.UserID
 	return t.topicRepo.SuggestTopic(ctx, *input)
 }
 
@@ -57,7 +63,32 @@ func (t *Topic) UpdateTopics(
 		}
 	}
 
-	return t.topicRepo.UpdateTopics(ctx, input)
+	before := make(map[int]map[string]interface{}, len(input))
+	beforeStatus := make(map[int]model.ApprovingStatusEnum, len(input))
+	for _, v := range input {
+		topic, err := t.topicRepo.GetTopic(ctx, v.Topic.ID)
+		if err != nil {
+			continue
+		}
+		before[v.Topic.ID] = topicAuditState(topic.Topic, metatopicIds(topic.Metatopics))
+		beforeStatus[v.Topic.ID] = topic.Topic.Status
+	}
+
+	updated, err := t.topicRepo.UpdateTopics(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range updated {
+		t.audit.Record(ctx, model.AuditActionTopicUpdated, model.AuditTargetTopic, v.Topic.ID,
+			before[v.Topic.ID], topicAuditState(v.Topic, metatopicIds(v.Metatopics)))
+
+		if v.Topic.SuggestedBy != nil && v.Topic.Status != beforeStatus[v.Topic.ID] {
+			t.notifyAuthor(ctx, v.Topic)
+		}
+	}
+
+	return updated, nil
 }
 
 func (t *Topic) GetTopics(
@@ -118,3 +149,45 @@ func (t *Topic) GetMetatopics(
 
 	return t.topicRepo.GetMetatopics(ctx, pageSize, pageNumber)
 }
+
+// notifyAuthor сообщает автору темы о решении модератора.
+func (t *Topic) notifyAuthor(ctx context.Context, topic model.Topic) {
+	var notificationType model.NotificationTypeEnum
+	var template string
+	switch topic.Status {
+	case model.StatusApproved:
+		notificationType = model.NotificationTypeTopicApproved
+		template = mailtemplate.MessageTopicApproved
+	case model.StatusDeclined:
+		notificationType = model.NotificationTypeTopicDeclined
+		template = mailtemplate.MessageTopicDeclined
+	default:
+		return
+	}
+
+	t.notifications.Notify(ctx, *topic.SuggestedBy, notificationType, topicNotificationKey(topic.ID, topic.Status),
+		map[string]interface{}{
+			"topicId":   topic.ID,
+			"topicName": topic.Name,
+		}, &NotificationEmail{
+			Category: model.NotificationCategoryDigest,
+			Template: template,
+			Data:     mailtemplate.TopicDecisionData{TopicName: topic.Name},
+		})
+}
+
+func topicAuditState(topic model.Topic, metatopicIds []int) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         topic.Name,
+		"status":       topic.Status,
+		"metatopicIds": metatopicIds,
+	}
+}
+
+func metatopicIds(metatopics []model.Metatopic) []int {
+	ids := make([]int, 0, len(metatopics))
+	for _, metatopic := range metatopics {
+		ids = append(ids, metatopic.ID)
+	}
+	return ids
+}
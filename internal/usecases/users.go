@@ -1,53 +1,116 @@
 package usecases
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/debate-io/service-auth/internal/domain/model"
 	"github.com/debate-io/service-auth/internal/domain/repo"
 	"github.com/debate-io/service-auth/internal/infrastructure/auth"
-	"github.com/debate-io/service-auth/internal/infrastructure/smtp"
+	"github.com/debate-io/service-auth/internal/infrastructure/imageurl"
+	"github.com/debate-io/service-auth/internal/infrastructure/imaging"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailtemplate"
+	"github.com/debate-io/service-auth/internal/infrastructure/password"
 	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
 	"github.com/debate-io/service-auth/internal/interface/server/middleware"
 	"github.com/debate-io/service-auth/internal/usecases/mappers"
-	murmur "github.com/whadron/go-murmurhash3"
-	"github.com/ztrue/tracerr"
-	"golang.org/x/crypto/bcrypt"
-
-	"image"
-	_ "image/gif"  // Подключаем пакеты для поддерживаемых форматов
-	_ "image/jpeg" // JPEG
-	_ "image/png"  // PNG
+	"go.uber.org/zap"
 )
 
 const (
 	CodeLength = 6
 	CodeTTL    = 5 // in minute
+
+	UnusedImageTTL = time.Hour
+	// blobDeletionBatchSize — сколько файлов удаляется из хранилища блобов за один запуск сборщика
+	blobDeletionBatchSize = 100
 )
 
 type User struct {
 	userRepo         repo.UserRepository
 	recoveryCodeRepo repo.RecoveryCodeRepository
+	emailChangeRepo  repo.EmailChangeRepository
 	gameStatsRepo    repo.GameStatsRepository
 	achievementRepo  repo.AchievmentsRepository
-	smtpSender       *smtp.Sender
+	mail             *Mail
 	authService      *auth.AuthService
+	passwordPolicy   *password.Policy
+	passwordHasher   password.Hasher
+	audit            *Audit
+	publicBaseUrl    string
+	imageCache       *ImageCache
+	bans             *BanCache
+	blobStore        repo.BlobStore
+	imageURLs        *imageurl.Builder
+	defaultAvatar    string
+	imageLimits      imaging.Limits
+	logger           *zap.Logger
+}
+
+// UserRepos — хранилища, с которыми работает User.
+type UserRepos struct {
+	Users         repo.UserRepository
+	RecoveryCodes repo.RecoveryCodeRepository
+	EmailChanges  repo.EmailChangeRepository
+	GameStats     repo.GameStatsRepository
+	Achievements  repo.AchievmentsRepository
+}
+
+// UserMail — письма пользователю. PublicBaseUrl — внешний адрес сервиса для ссылок из писем.
+type UserMail struct {
+	Mail          *Mail
+	PublicBaseUrl string
+}
+
+// UserPasswords — требования к паролям и их хеширование.
+type UserPasswords struct {
+	Policy *password.Policy
+	Hasher password.Hasher
+}
+
+// UserImages — хранение и выдача аватаров. DefaultAvatar — стиль аватара для пользователей без своего.
+type UserImages struct {
+	Cache         *ImageCache
+	BlobStore     repo.BlobStore
+	URLs          *imageurl.Builder
+	DefaultAvatar string
+	Limits        imaging.Limits
 }
 
-func NewUserUseCases(userRepo repo.UserRepository, recoveryCodeRepo repo.RecoveryCodeRepository, gameStatsRepo repo.GameStatsRepository, achievementRepo repo.AchievmentsRepository, smtpClient *smtp.Sender, authService *auth.AuthService) *User {
+func NewUserUseCases(
+	repos UserRepos,
+	mail UserMail,
+	passwords UserPasswords,
+	images UserImages,
+	bans *BanCache,
+	authService *auth.AuthService,
+	audit *Audit,
+	logger *zap.Logger,
+) *User {
 	return &User{
-		userRepo:         userRepo,
-		recoveryCodeRepo: recoveryCodeRepo,
-		gameStatsRepo:    gameStatsRepo,
-		achievementRepo:  achievementRepo,
-		smtpSender:       smtpClient,
+		userRepo:         repos.Users,
+		recoveryCodeRepo: repos.RecoveryCodes,
+		emailChangeRepo:  repos.EmailChanges,
+		gameStatsRepo:    repos.GameStats,
+		achievementRepo:  repos.Achievements,
+		mail:             mail.Mail,
 		authService:      authService,
+		passwordPolicy:   passwords.Policy,
+		passwordHasher:   passwords.Hasher,
+		audit:            audit,
+		publicBaseUrl:    mail.PublicBaseUrl,
+		imageCache:       images.Cache,
+		bans:             bans,
+		blobStore:        images.BlobStore,
+		imageURLs:        images.URLs,
+		defaultAvatar:    images.DefaultAvatar,
+		imageLimits:      images.Limits,
+		logger:           logger,
 	}
 }
 
@@ -63,6 +126,10 @@ func (u *User) CreateUser(
 		Password:  input.Password,
 		Image:     nil,
 		Role:      model.RoleDefaultUser,
+		Locale:    u.mail.DefaultLocale(),
+	}
+	if input.Locale != nil {
+		user.Locale = mappers.MapLocaleFromDTO(*input.Locale)
 	}
 
 	if err := user.Validate(); err != nil {
@@ -70,12 +137,19 @@ func (u *User) CreateUser(
 			Error: mappers.NewDTOError(gen.ErrorValidation)}, nil
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if violations := u.passwordPolicy.Check(input.Password, input.Username, input.Email); len(violations) != 0 {
+		return &gen.RegisterUserOutput{
+			Error:              mappers.NewDTOError(gen.ErrorWeakPassword),
+			PasswordViolations: mappers.MapPasswordViolationsToDTO(violations),
+		}, nil
+	}
+
+	hashedPassword, err := u.passwordHasher.Hash(input.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 
 	_, err = u.userRepo.CreateUser(ctx, user)
 	if err != nil {
@@ -97,7 +171,7 @@ func (u *User) CreateUser(
 		return nil, err
 	}
 
-	return &gen.RegisterUserOutput{User: mappers.MapUserToDTO(user), Jwt: &jwt}, nil
+	return &gen.RegisterUserOutput{User: mappers.MapUserToDTO(user, u.imageURL(user)), Jwt: &jwt}, nil
 }
 
 func (u *User) AuthenticateUser(
@@ -114,12 +188,32 @@ func (u *User) AuthenticateUser(
 		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
+	ok, needsRehash, err := u.passwordHasher.Verify(user.Password, input.Password)
 	if err != nil {
+		if !errors.Is(err, password.ErrUnknownHash) {
+			return nil, err
+		}
+
+		// Испорченный хеш — забота администратора, а не пользователя: для него это неверный пароль
+		u.logger.Error("unrecognised password hash", zap.Int("userId", user.ID), zap.Error(err))
+		ok = false
+	}
+	if !ok {
 		return &gen.AuthenticateUserOutput{
 			Error: mappers.NewDTOError(gen.ErrorInvalidCredentials)}, nil
 	}
 
+	if user.IsBanned() {
+		return &gen.AuthenticateUserOutput{
+			Error: mappers.NewDTOError(gen.ErrorBanned)}, nil
+	}
+
+	// Пароль известен только в момент входа, поэтому тут же переводим хеш на текущий алгоритм.
+	// Ошибка не мешает входу: хеш обновится при следующей попытке.
+	if needsRehash {
+		u.rehashPassword(ctx, user.ID, input.Password)
+	}
+
 	claims, err := model.NewAuthClaims(user.ID, user.Email, user.Role, u.authService.GetDaysAuthExpires())
 	if err != nil {
 		return nil, err
@@ -133,6 +227,35 @@ func (u *User) AuthenticateUser(
 	return &gen.AuthenticateUserOutput{Jwt: &jwt}, nil
 }
 
+func (u *User) rehashPassword(ctx context.Context, userId int, plain string) {
+	hashedPassword, err := u.passwordHasher.Hash(plain)
+	if err == nil {
+		err = u.userRepo.UpdateUserPassword(ctx, userId, hashedPassword)
+	}
+	if err != nil {
+		u.logger.Warn("failed to rehash password", zap.Int("userId", userId), zap.Error(err))
+	}
+}
+
+// IsUserBanned implements middleware.BanChecker. Токен удалённого пользователя тоже не действует.
+// Ответ кешируется на banCacheTTL.
+func (u *User) IsUserBanned(ctx context.Context, userId int) (bool, error) {
+	if banned, ok := u.bans.get(userId); ok {
+		return banned, nil
+	}
+
+	banned := true
+	user, err := u.userRepo.FindUserByID(ctx, userId)
+	if err == nil {
+		banned = user.IsBanned()
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		return false, err
+	}
+
+	u.bans.add(userId, banned)
+	return banned, nil
+}
+
 func (u *User) GetUser(
 	ctx context.Context,
 	input gen.GetUserInput,
@@ -162,7 +285,7 @@ func (u *User) GetUser(
 		return nil, err
 	}
 
-	return &gen.GetUserOutput{User: mappers.MapUserToDTO(user)}, nil
+	return &gen.GetUserOutput{User: mappers.MapUserToDTO(user, u.imageURL(user))}, nil
 }
 
 func (u *User) GetUsers(ctx context.Context, limit int, offset int) (*gen.GetAllUsersOutput, error) {
@@ -191,7 +314,7 @@ func (u *User) GetUsers(ctx context.Context, limit int, offset int) (*gen.GetAll
 		return nil, err
 	}
 	fmt.Printf("%+v", users)
-	return &gen.GetAllUsersOutput{Users: mappers.MapUsersToDTO(users)}, nil
+	return &gen.GetAllUsersOutput{Users: mappers.MapUsersToDTO(users, u.imageURL)}, nil
 
 }
 
@@ -219,8 +342,10 @@ func (u *User) GetGamesStats(
 	}
 
 	result := &gen.GetGamesStatsOutput{
-		GamesAmount: stat.TotalGamesStats.GamesAmount,
-		WinsAmount:  stat.TotalGamesStats.WinsAmount,
+		GamesAmount:    stat.TotalGamesStats.GamesAmount,
+		WinsAmount:     stat.TotalGamesStats.WinsAmount,
+		DrawsAmount:    stat.TotalGamesStats.DrawsAmount,
+		ForfeitsAmount: stat.TotalGamesStats.ForfeitsAmount,
 	}
 	if stat.TotalGamesStats.GamesAmount != 0 {
 		result.WinsPercents = 100. * float64(stat.TotalGamesStats.WinsAmount) / float64(stat.TotalGamesStats.GamesAmount)
@@ -228,9 +353,11 @@ func (u *User) GetGamesStats(
 
 	for metatopic, stat := range stat.MetaTopicStats {
 		element := &gen.MetaTopicsStats{
-			MetaTopic:   metatopic,
-			GamesAmount: stat.GamesAmount,
-			WinsAmount:  stat.WinsAmount,
+			MetaTopic:      metatopic,
+			GamesAmount:    stat.GamesAmount,
+			WinsAmount:     stat.WinsAmount,
+			DrawsAmount:    stat.DrawsAmount,
+			ForfeitsAmount: stat.ForfeitsAmount,
 		}
 
 		if stat.GamesAmount != 0 {
@@ -254,6 +381,13 @@ func (u *User) UpdateUser(
 		}, nil
 	}
 
+	// По токену имперсонации нельзя менять учётные данные пользователя
+	if claims.IsImpersonated() && (input.Password != nil || input.Email != nil) {
+		return &gen.UpdateUserOutput{
+			Error: mappers.NewDTOError(gen.ErrorUnauthorized),
+		}, nil
+	}
+
 	user, err := u.userRepo.FindUserByID(ctx, input.ID)
 	if err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
@@ -264,21 +398,49 @@ func (u *User) UpdateUser(
 		return nil, err
 	}
 
+	before := userAuditState(user)
+
 	if input.Username != nil {
 		user.Username = *input.Username
 	}
-	if input.ImageID != nil {
-		user.Image.ID = *input.ImageID
+	if input.Locale != nil {
+		user.Locale = mappers.MapLocaleFromDTO(*input.Locale)
+	}
+	if input.ImageID != nil && *input.ImageID <= 0 {
+		return &gen.UpdateUserOutput{
+			Error: mappers.NewDTOError(gen.ErrorValidation)}, nil
+	}
+
+	// Почту проверяем заранее, чтобы не сохранить остальные поля с ошибкой в ней
+	var newEmail string
+	if input.Email != nil && *input.Email != user.Email {
+		var dtoErr *gen.Error
+		if newEmail, dtoErr, err = u.checkNewEmail(ctx, user, *input.Email); err != nil {
+			return nil, err
+		}
+		if dtoErr != nil {
+			return &gen.UpdateUserOutput{Error: dtoErr}, nil
+		}
 	}
+
 	if input.Password != nil {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*input.Password), bcrypt.DefaultCost)
+		email := user.Email
+		if newEmail != "" {
+			email = newEmail
+		}
+
+		if violations := u.passwordPolicy.Check(*input.Password, user.Username, email); len(violations) != 0 {
+			return &gen.UpdateUserOutput{
+				Error:              mappers.NewDTOError(gen.ErrorWeakPassword),
+				PasswordViolations: mappers.MapPasswordViolationsToDTO(violations),
+			}, nil
+		}
+
+		hashedPassword, err := u.passwordHasher.Hash(*input.Password)
 		if err != nil {
 			return nil, err
 		}
-		user.Password = string(hashedPassword)
-	}
-	if input.Email != nil {
-		user.Email = *input.Email
+		user.Password = hashedPassword
 	}
 
 	if err := user.Validate(); err != nil {
@@ -290,7 +452,34 @@ func (u *User) UpdateUser(
 		return nil, err
 	}
 
-	return &gen.UpdateUserOutput{User: mappers.MapUserToDTO(user)}, nil
+	// Вернуть можно только своё прежнее изображение, чужой id — ErrNotFound
+	if input.ImageID != nil && *input.ImageID != user.ImageId {
+		if err := u.userRepo.SetUserImage(ctx, user.ID, *input.ImageID); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return &gen.UpdateUserOutput{
+					Error: mappers.NewDTOError(gen.ErrorNotFound)}, nil
+			}
+			return nil, err
+		}
+		u.imageCache.invalidate(user.ID)
+
+		if user, err = u.userRepo.FindUserByID(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Почта меняется только после подтверждения с нового адреса
+	if newEmail != "" {
+		if err := u.requestEmailChange(ctx, user, newEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	after := userAuditState(user)
+	after["passwordChanged"] = input.Password != nil
+	u.audit.Record(ctx, model.AuditActionUserUpdated, model.AuditTargetUser, user.ID, before, after)
+
+	return &gen.UpdateUserOutput{User: mappers.MapUserToDTO(user, u.imageURL(user))}, nil
 }
 
 func (u *User) RecoveryPassword(ctx context.Context, input gen.RecoveryPasswordInput) (*gen.RecoveryPasswordOutput, error) {
@@ -310,17 +499,24 @@ func (u *User) RecoveryPassword(ctx context.Context, input gen.RecoveryPasswordI
 		Code:      generateCode(CodeLength),
 		ExpiredAt: time.Now().Add(CodeTTL * time.Minute),
 	}
-	_, err = u.recoveryCodeRepo.CreateRecoveryCode(ctx, code)
+	email, err := u.mail.ComposeEmail(ctx, user, user.Email, model.NotificationCategorySecurity, mailtemplate.MessagePasswordRecovery, mailtemplate.PasswordRecoveryData{
+		Code:       code.Code,
+		TTLMinutes: CodeTTL,
+	})
 	if err != nil {
 		return nil, err
 	}
+	if email != nil {
+		email.ExpiresAt = &code.ExpiredAt
+	}
 
-	// TODO: Add rendering template HTML message
-	err = u.smtpSender.SendPlainMessage("Код для восстановления пароля", code.Code, input.Email)
+	_, err = u.recoveryCodeRepo.CreateRecoveryCode(ctx, code, email)
 	if err != nil {
 		return nil, err
 	}
 
+	u.audit.Record(ctx, model.AuditActionUserPasswordRecovery, model.AuditTargetUser, user.ID, nil, nil)
+
 	return &gen.RecoveryPasswordOutput{}, nil
 }
 
@@ -340,7 +536,7 @@ func (u *User) VerifyRecoveryCode(ctx context.Context, input gen.VerifyRecoveryC
 
 func (u *User) UpdatePassword(ctx context.Context, input gen.UpdatePasswordInput) (*gen.UpdatePasswordOutput, error) {
 	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
-	if claims == nil || claims.Role != model.RoleAdmin && claims.UserID != input.ID {
+	if claims == nil || claims.IsImpersonated() || claims.Role != model.RoleAdmin && claims.UserID != input.ID {
 		return &gen.UpdatePasswordOutput{
 			Error: mappers.NewDTOError(gen.ErrorUnauthorized),
 		}, nil
@@ -356,17 +552,24 @@ func (u *User) UpdatePassword(ctx context.Context, input gen.UpdatePasswordInput
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.OldPassword)); err != nil {
+	if ok, _, err := u.passwordHasher.Verify(user.Password, input.OldPassword); err != nil || !ok {
 		return &gen.UpdatePasswordOutput{
 			Error: mappers.NewDTOError(gen.ErrorInvalidCredentials)}, nil
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if violations := u.passwordPolicy.Check(input.NewPassword, user.Username, user.Email); len(violations) != 0 {
+		return &gen.UpdatePasswordOutput{
+			Error:              mappers.NewDTOError(gen.ErrorWeakPassword),
+			PasswordViolations: mappers.MapPasswordViolationsToDTO(violations),
+		}, nil
+	}
+
+	hashedPassword, err := u.passwordHasher.Hash(input.NewPassword)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 
 	if _, err := u.userRepo.UpdateUser(ctx, user); err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
@@ -376,12 +579,14 @@ func (u *User) UpdatePassword(ctx context.Context, input gen.UpdatePasswordInput
 		return &gen.UpdatePasswordOutput{}, err
 	}
 
+	u.audit.Record(ctx, model.AuditActionUserPasswordChanged, model.AuditTargetUser, user.ID, nil, nil)
+
 	return &gen.UpdatePasswordOutput{}, nil
 }
 
 func (u *User) UpdateEmail(ctx context.Context, input gen.UpdateEmailInput) (*gen.UpdateEmailOutput, error) {
 	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
-	if claims == nil || claims.Role != model.RoleAdmin && claims.UserID != input.ID {
+	if claims == nil || claims.IsImpersonated() || claims.Role != model.RoleAdmin && claims.UserID != input.ID {
 		return &gen.UpdateEmailOutput{
 			Error: mappers.NewDTOError(gen.ErrorUnauthorized),
 		}, nil
@@ -397,21 +602,72 @@ func (u *User) UpdateEmail(ctx context.Context, input gen.UpdateEmailInput) (*ge
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+	if ok, _, err := u.passwordHasher.Verify(user.Password, input.Password); err != nil || !ok {
 		return &gen.UpdateEmailOutput{
 			Error: mappers.NewDTOError(gen.ErrorInvalidCredentials)}, nil
 	}
 
-	user.Email = input.Email
-	if _, err := u.userRepo.UpdateUser(ctx, user); err != nil {
+	newEmail, dtoErr, err := u.checkNewEmail(ctx, user, input.Email)
+	if err != nil {
+		return nil, err
+	}
+	if dtoErr != nil {
+		return &gen.UpdateEmailOutput{Error: dtoErr}, nil
+	}
+
+	if err := u.requestEmailChange(ctx, user, newEmail); err != nil {
+		return nil, err
+	}
+
+	return &gen.UpdateEmailOutput{}, nil
+}
+
+func (u *User) ImpersonateUser(ctx context.Context, input gen.ImpersonateUserInput) (*gen.ImpersonateUserOutput, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil || claims.IsImpersonated() || claims.Role != model.RoleAdmin {
+		return &gen.ImpersonateUserOutput{
+			Error: mappers.NewDTOError(gen.ErrorUnauthorized),
+		}, nil
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" || input.UserID == claims.UserID {
+		return &gen.ImpersonateUserOutput{
+			Error: mappers.NewDTOError(gen.ErrorValidation)}, nil
+	}
+
+	user, err := u.userRepo.FindUserByID(ctx, input.UserID)
+	if err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
-			return &gen.UpdateEmailOutput{
+			return &gen.ImpersonateUserOutput{
 				Error: mappers.NewDTOError(gen.ErrorNotFound)}, nil
 		}
-		return &gen.UpdateEmailOutput{}, err
+
+		return nil, err
 	}
 
-	return &gen.UpdateEmailOutput{}, nil
+	// Поддержка смотрит глазами игроков, но не других администраторов
+	if user.Role == model.RoleAdmin {
+		return &gen.ImpersonateUserOutput{
+			Error: mappers.NewDTOError(gen.ErrorUnauthorized),
+		}, nil
+	}
+
+	impersonationClaims := model.NewImpersonationClaims(user, claims, reason, u.authService.GetImpersonationExpires())
+	jwt, err := u.authService.GenerateImpersonationToken(impersonationClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := impersonationClaims.ExpiredAt.Time
+	u.audit.Record(ctx, model.AuditActionUserImpersonated, model.AuditTargetUser, user.ID, nil,
+		map[string]interface{}{
+			"reason":    reason,
+			"expiresAt": expiresAt,
+		},
+	)
+
+	return &gen.ImpersonateUserOutput{Jwt: &jwt, ExpiresAt: &expiresAt}, nil
 }
 
 func (u *User) GetAchievmentsByUserId(ctx context.Context, userId int, limit int, offset int) ([]*gen.Achievement, error) {
@@ -443,11 +699,18 @@ func (u *User) ResetPassword(ctx context.Context, input gen.ResetPasswordInput)
 		return nil, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if violations := u.passwordPolicy.Check(input.Password, code.User.Username, code.User.Email); len(violations) != 0 {
+		return &gen.ResetPasswordOutput{
+			Error:              mappers.NewDTOError(gen.ErrorWeakPassword),
+			PasswordViolations: mappers.MapPasswordViolationsToDTO(violations),
+		}, nil
+	}
+
+	hashedPassword, err := u.passwordHasher.Hash(input.Password)
 	if err != nil {
 		return nil, err
 	}
-	code.User.Password = string(hashedPassword)
+	code.User.Password = hashedPassword
 
 	_, err = u.userRepo.UpdateUser(ctx, code.User)
 	if err != nil {
@@ -459,28 +722,18 @@ func (u *User) ResetPassword(ctx context.Context, input gen.ResetPasswordInput)
 		return nil, err
 	}
 
-	return &gen.ResetPasswordOutput{}, nil
-}
-
-func (u *User) UploadImage(ctx context.Context, userId int, image []byte) error {
-	contentType, err := getContentType(image)
-	if err != nil {
-		return tracerr.Wrap(err)
-	}
+	u.audit.Record(ctx, model.AuditActionUserPasswordReset, model.AuditTargetUser, code.User.ID, nil, nil)
 
-	err = u.userRepo.UploadImage(ctx, userId, image, getHash(image), contentType)
-	if err != nil {
-		return tracerr.Wrap(err)
-	}
-	return nil
+	return &gen.ResetPasswordOutput{}, nil
 }
 
-func (u *User) DownloadImage(ctx context.Context, userId int) ([]byte, string, error) {
-	image, contentType, err := u.userRepo.DownloadImage(ctx, userId)
-	if err != nil {
-		return nil, "", tracerr.Wrap(err)
+func userAuditState(user *model.User) map[string]interface{} {
+	return map[string]interface{}{
+		"username": user.Username,
+		"email":    user.Email,
+		"imageId":  user.ImageId,
+		"locale":   user.Locale,
 	}
-	return image, contentType, nil
 }
 
 func generateCode(length int) string {
@@ -492,15 +745,3 @@ func generateCode(length int) string {
 
 	return string(code)
 }
-
-func getHash(file []byte) []byte {
-	return murmur.NewX64_128(1).Sum(file)
-}
-func getContentType(file []byte) (string, error) {
-	reader := bytes.NewReader(file)
-	_, format, err := image.DecodeConfig(reader) // Определяем конфигурацию изображения
-	if err != nil {
-		return "", fmt.Errorf("не удалось определить формат изображения: %w", err)
-	}
-	return format, nil
-}
@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/mailtemplate"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+const (
+	EmailChangeCodeTTL = 15 * time.Minute
+	EmailRevertTTL     = 7 * 24 * time.Hour
+
+	revertTokenLength = 32
+)
+
+// EmailRevertPath — адрес отмены смены почты. По нему строится ссылка в письме и регистрируется маршрут.
+const EmailRevertPath = "/user/email/revert"
+
+// checkNewEmail проверяет новый адрес до любых изменений: он должен быть корректным,
+// отличаться от текущего и не принадлежать другому аккаунту. Возвращает адрес без пробелов по краям.
+func (u *User) checkNewEmail(ctx context.Context, user *model.User, newEmail string) (string, *gen.Error, error) {
+	newEmail = strings.TrimSpace(newEmail)
+	if err := validation.Validate(newEmail, validation.Required, is.Email); err != nil {
+		return "", mappers.NewDTOError(gen.ErrorValidation), nil
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return "", mappers.NewDTOError(gen.ErrorValidation), nil
+	}
+
+	if _, err := u.userRepo.FindUserByEmail(ctx, newEmail); err == nil {
+		return "", mappers.NewDTOError(gen.ErrorAlreadyExist), nil
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		return "", nil, err
+	}
+
+	return newEmail, nil, nil
+}
+
+// requestEmailChange создаёт заявку на смену почты и отправляет код подтверждения на новый адрес,
+// уже проверенный checkNewEmail. Почта пользователя при этом не меняется.
+func (u *User) requestEmailChange(ctx context.Context, user *model.User, newEmail string) error {
+	revertToken, err := generateToken(revertTokenLength)
+	if err != nil {
+		return err
+	}
+
+	change := &model.EmailChange{
+		UserID:      user.ID,
+		OldEmail:    user.Email,
+		NewEmail:    newEmail,
+		Code:        generateCode(CodeLength),
+		RevertToken: revertToken,
+		ExpiredAt:   time.Now().Add(EmailChangeCodeTTL),
+	}
+
+	email, err := u.mail.ComposeEmail(ctx, user, change.NewEmail, model.NotificationCategorySecurity, mailtemplate.MessageEmailChangeCode, mailtemplate.EmailChangeCodeData{
+		Code:       change.Code,
+		TTLMinutes: int(EmailChangeCodeTTL / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+	if email != nil {
+		email.ExpiresAt = &change.ExpiredAt
+	}
+
+	change, err = u.emailChangeRepo.CreateEmailChange(ctx, change, email)
+	if err != nil {
+		return err
+	}
+
+	u.audit.Record(ctx, model.AuditActionUserEmailChangeRequested, model.AuditTargetUser, user.ID,
+		map[string]interface{}{"email": change.OldEmail},
+		map[string]interface{}{"pendingEmail": change.NewEmail},
+	)
+
+	return nil
+}
+
+func (u *User) ConfirmEmailChange(ctx context.Context, input gen.ConfirmEmailChangeInput) (*gen.ConfirmEmailChangeOutput, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil || claims.IsImpersonated() {
+		return &gen.ConfirmEmailChangeOutput{
+			Error: mappers.NewDTOError(gen.ErrorUnauthorized),
+		}, nil
+	}
+
+	change, err := u.emailChangeRepo.FindPendingEmailChange(ctx, claims.UserID, input.Code)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return &gen.ConfirmEmailChangeOutput{
+				Error: mappers.NewDTOError(gen.ErrorNotFound)}, nil
+		}
+
+		return nil, err
+	}
+
+	user, err := u.userRepo.FindUserByID(ctx, change.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revertExpiredAt := time.Now().Add(EmailRevertTTL)
+	change.RevertExpiredAt = &revertExpiredAt
+
+	email, err := u.mail.ComposeEmail(ctx, user, change.OldEmail, model.NotificationCategorySecurity, mailtemplate.MessageEmailChanged, mailtemplate.EmailChangedData{
+		NewEmail:        change.NewEmail,
+		RevertLink:      u.emailRevertLink(change.RevertToken),
+		RevertExpiresAt: revertExpiredAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if email != nil {
+		email.ExpiresAt = &revertExpiredAt
+	}
+
+	if err := u.emailChangeRepo.ConfirmEmailChange(ctx, change, email); err != nil {
+		if errors.Is(err, repo.ErrAlreadyExist) {
+			return &gen.ConfirmEmailChangeOutput{
+				Error: mappers.NewDTOError(gen.ErrorAlreadyExist)}, nil
+		}
+		if errors.Is(err, repo.ErrNotFound) {
+			return &gen.ConfirmEmailChangeOutput{
+				Error: mappers.NewDTOError(gen.ErrorNotFound)}, nil
+		}
+
+		return nil, err
+	}
+
+	u.audit.Record(ctx, model.AuditActionUserEmailChanged, model.AuditTargetUser, change.UserID,
+		map[string]interface{}{"email": change.OldEmail},
+		map[string]interface{}{"email": change.NewEmail},
+	)
+
+	user.Email = change.NewEmail
+	user.UpdatedAt = change.ConfirmedAt.UTC()
+
+	return &gen.ConfirmEmailChangeOutput{User: mappers.MapUserToDTO(user, u.imageURL(user))}, nil
+}
+
+// RevertEmailChange возвращает прежнюю почту по токену из письма, отправленного на старый адрес.
+// Авторизация не требуется: владение старым ящиком и есть подтверждение.
+func (u *User) RevertEmailChange(ctx context.Context, token string) error {
+	if token == "" {
+		return repo.ErrValidation
+	}
+
+	change, err := u.emailChangeRepo.FindEmailChangeByRevertToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := u.emailChangeRepo.RevertEmailChange(ctx, change); err != nil {
+		return err
+	}
+
+	u.audit.Record(ctx, model.AuditActionUserEmailChangeReverted, model.AuditTargetUser, change.UserID,
+		map[string]interface{}{"email": change.NewEmail},
+		map[string]interface{}{"email": change.OldEmail},
+	)
+
+	return nil
+}
+
+func (u *User) emailRevertLink(token string) string {
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimSuffix(u.publicBaseUrl, "/"), EmailRevertPath, url.QueryEscape(token))
+}
+
+func generateToken(length int) (string, error) {
+	token := make([]byte, length)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
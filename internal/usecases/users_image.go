@@ -0,0 +1,358 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/debate-io/service-auth/internal/domain/model"
+	"github.com/debate-io/service-auth/internal/domain/repo"
+	"github.com/debate-io/service-auth/internal/infrastructure/imaging"
+	"github.com/debate-io/service-auth/internal/interface/graphql/gen"
+	"github.com/debate-io/service-auth/internal/interface/server/middleware"
+	"github.com/debate-io/service-auth/internal/usecases/mappers"
+	"github.com/ztrue/tracerr"
+)
+
+// UploadImage нарезает загруженное изображение на квадратные варианты из imaging.Sizes и сохраняет их в хранилище блобов.
+// Исходный файл не хранится, images.storage_key указывает на самый большой вариант.
+// Если такой же файл уже загружали, пользователь получает ссылку на существующее изображение.
+func (u *User) UploadImage(ctx context.Context, userId int, data []byte) error {
+	defer u.imageCache.invalidate(userId)
+
+	if err := imaging.Check(data, u.imageLimits); err != nil {
+		return wrapImageError(err)
+	}
+
+	user, err := u.userRepo.FindUserByID(ctx, userId)
+	if err != nil {
+		return tracerr.Wrap(err)
+	}
+
+	hash := getHash(data)
+	attached, err := u.userRepo.AttachImageByHash(ctx, userId, hash)
+	if err != nil {
+		return tracerr.Wrap(err)
+	}
+	if !attached {
+		if err := u.storeImage(ctx, userId, hash, data); err != nil {
+			return err
+		}
+	}
+
+	u.audit.Record(ctx, model.AuditActionUserAvatarChanged, model.AuditTargetUser, userId,
+		map[string]interface{}{"imageId": user.ImageId},
+		map[string]interface{}{"hash": fmt.Sprintf("%x", hash)},
+	)
+
+	return nil
+}
+
+func (u *User) storeImage(ctx context.Context, userId int, hash []byte, data []byte) error {
+	variants, err := imaging.Process(data, u.imageLimits)
+	if err != nil {
+		return wrapImageError(err)
+	}
+
+	// Случайная часть ключа не даёт новой загрузке переиспользовать ключи изображения,
+	// которое прямо сейчас удаляет сборщик мусора
+	nonce, err := generateToken(4)
+	if err != nil {
+		return tracerr.Wrap(err)
+	}
+
+	image := &model.Image{Hash: hash}
+	largest := 0
+	keys := make([]string, 0, len(variants))
+	imageVariants := make([]*model.ImageVariant, 0, len(variants))
+	for _, variant := range variants {
+		key := imageBlobKey(hash, nonce, fmt.Sprint(variant.Size), variant.Format)
+		if err := u.blobStore.Put(ctx, key, variant.Data, "image/"+variant.Format); err != nil {
+			u.deleteBlobs(ctx, keys)
+			return tracerr.Wrap(err)
+		}
+		keys = append(keys, key)
+
+		imageVariants = append(imageVariants, &model.ImageVariant{
+			Size:        variant.Size,
+			ContentType: variant.Format,
+			StorageKey:  key,
+		})
+		if variant.Format != imaging.FormatWebP && variant.Size > largest {
+			largest = variant.Size
+			image.StorageKey, image.ContentType = key, variant.Format
+		}
+	}
+
+	created, err := u.userRepo.UploadImage(ctx, userId, image, imageVariants)
+	if err != nil || !created {
+		u.deleteBlobs(ctx, keys)
+	}
+	if err != nil {
+		return tracerr.Wrap(err)
+	}
+	return nil
+}
+
+// UploadAvatar загружает аватар текущего пользователя из GraphQL-запроса.
+func (u *User) UploadAvatar(ctx context.Context, file io.Reader) (*gen.UploadAvatarOutput, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return &gen.UploadAvatarOutput{
+			Error: mappers.NewDTOError(gen.ErrorUnauthorized)}, nil
+	}
+
+	// Читаем на байт больше лимита, чтобы imaging.Check отличил слишком большой файл
+	data, err := io.ReadAll(io.LimitReader(file, u.imageLimits.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.UploadImage(ctx, claims.UserID, data); err != nil {
+		if dtoErr := mapImageErrorToDTO(err); dtoErr != nil {
+			return &gen.UploadAvatarOutput{Error: dtoErr}, nil
+		}
+		return nil, err
+	}
+
+	user, err := u.userRepo.FindUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gen.UploadAvatarOutput{User: mappers.MapUserToDTO(user, u.imageURL(user))}, nil
+}
+
+// DeleteAvatar убирает аватар текущего пользователя. Само изображение удалит сборщик мусора.
+func (u *User) DeleteAvatar(ctx context.Context) (*gen.DeleteAvatarOutput, error) {
+	claims := ctx.Value(middleware.JwtClaimsKey).(*model.Claims)
+	if claims == nil {
+		return &gen.DeleteAvatarOutput{
+			Error: mappers.NewDTOError(gen.ErrorUnauthorized)}, nil
+	}
+
+	user, err := u.userRepo.FindUserByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return &gen.DeleteAvatarOutput{
+				Error: mappers.NewDTOError(gen.ErrorNotFound)}, nil
+		}
+		return nil, err
+	}
+
+	if user.ImageId != 0 {
+		if err := u.userRepo.SetUserImage(ctx, user.ID, 0); err != nil {
+			return nil, err
+		}
+		u.imageCache.invalidate(user.ID)
+
+		u.audit.Record(ctx, model.AuditActionUserAvatarChanged, model.AuditTargetUser, user.ID,
+			map[string]interface{}{"imageId": user.ImageId},
+			map[string]interface{}{"imageId": nil},
+		)
+		user.ImageId, user.Image = 0, nil
+	}
+
+	return &gen.DeleteAvatarOutput{User: mappers.MapUserToDTO(user, u.imageURL(user))}, nil
+}
+
+func mapImageErrorToDTO(err error) *gen.Error {
+	switch {
+	case errors.Is(err, imaging.ErrFileTooLarge), errors.Is(err, imaging.ErrImageTooLarge):
+		return mappers.NewDTOError(gen.ErrorImageTooLarge)
+	case errors.Is(err, imaging.ErrUnsupportedFormat):
+		return mappers.NewDTOError(gen.ErrorUnsupportedImageFormat)
+	case errors.Is(err, repo.ErrValidation):
+		return mappers.NewDTOError(gen.ErrorValidation)
+	case errors.Is(err, repo.ErrNotFound):
+		return mappers.NewDTOError(gen.ErrorNotFound)
+	default:
+		return nil
+	}
+}
+
+// ImageLimits возвращает ограничения на загружаемые изображения.
+func (u *User) ImageLimits() imaging.Limits {
+	return u.imageLimits
+}
+
+// wrapImageError помечает ошибки проверки изображения как ErrValidation, сохраняя исходную причину для errors.Is.
+func wrapImageError(err error) error {
+	for _, target := range []error{
+		imaging.ErrUnsupportedImage,
+		imaging.ErrUnsupportedFormat,
+		imaging.ErrFileTooLarge,
+		imaging.ErrImageTooLarge,
+	} {
+		if errors.Is(err, target) {
+			return tracerr.Errorf("%w: %w", repo.ErrValidation, err)
+		}
+	}
+
+	return tracerr.Wrap(err)
+}
+
+// DownloadImage отдаёт аватар стороной не меньше size, в WebP, если клиент его поддерживает.
+// Пользователям без аватара рисуется картинка по умолчанию.
+func (u *User) DownloadImage(ctx context.Context, userId int, size int, webp bool) (*model.ImageContent, error) {
+	contentTypes := []string{imaging.FormatJPEG, imaging.FormatPNG}
+	if webp {
+		contentTypes = []string{imaging.FormatWebP}
+	}
+
+	key := imageCacheKey{userId: userId, size: size, webp: webp}
+	if image, ok := u.imageCache.get(key); ok {
+		return image, nil
+	}
+
+	image, err := u.userRepo.DownloadImage(ctx, userId, size, contentTypes)
+	if errors.Is(err, repo.ErrNotFound) {
+		image, err = u.defaultImage(ctx, userId, size)
+	}
+	if err != nil {
+		return nil, tracerr.Wrap(err)
+	}
+
+	if image.File == nil && image.StorageKey != "" {
+		image.File, err = u.blobStore.Get(ctx, image.StorageKey)
+		if err != nil {
+			return nil, tracerr.Wrap(err)
+		}
+	}
+
+	u.imageCache.add(key, image)
+	return image, nil
+}
+
+// defaultImage рисует детерминированный аватар по id пользователя: identicon в PNG или инициалы в SVG.
+func (u *User) defaultImage(ctx context.Context, userId int, size int) (*model.ImageContent, error) {
+	user, err := u.userRepo.FindUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	side := min(size, imaging.Sizes[len(imaging.Sizes)-1])
+	seed := fmt.Sprintf("user:%d", user.ID)
+
+	image := &model.ImageContent{Size: side}
+	if u.defaultAvatar == imaging.DefaultStyleInitials {
+		image.ContentType = imaging.FormatSVG
+		image.File = imaging.InitialsSVG(seed, user.Username, side)
+	} else {
+		image.ContentType = imaging.FormatPNG
+		image.File, err = imaging.Identicon(seed, side)
+		if err != nil {
+			return nil, err
+		}
+	}
+	image.Hash = getHash(image.File)
+
+	return image, nil
+}
+
+// CollectUnusedImages удаляет изображения, на которые дольше UnusedImageTTL не ссылается ни один пользователь,
+// и их файлы из хранилища блобов. Файл, который не удалось удалить, остаётся в очереди до следующего запуска.
+func (u *User) CollectUnusedImages(ctx context.Context) (int, error) {
+	deleted, err := u.userRepo.DeleteUnreferencedImages(ctx, time.Now().Add(-UnusedImageTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	keys, err := u.userRepo.GetBlobDeletions(ctx, blobDeletionBatchSize)
+	if err != nil {
+		return deleted, err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := u.blobStore.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+			if err := u.userRepo.FailBlobDeletion(ctx, key, err.Error()); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if err := u.userRepo.CompleteBlobDeletion(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// MigrateImagesToBlobStore переносит файлы, хранящиеся прямо в таблицах изображений, в хранилище блобов.
+// Возвращает количество перенесённых файлов.
+func MigrateImagesToBlobStore(ctx context.Context, userRepo repo.UserRepository, blobStore repo.BlobStore, batchSize int) (int, error) {
+	moved := 0
+	for {
+		files, err := userRepo.GetInlineImageFiles(ctx, batchSize)
+		if err != nil {
+			return moved, err
+		}
+		if len(files) == 0 {
+			return moved, nil
+		}
+
+		for _, file := range files {
+			name := "original"
+			if file.Variant {
+				name = fmt.Sprint(file.Size)
+			}
+			key := imageBlobKey(file.Hash, "migrated", name, file.ContentType)
+
+			if err := blobStore.Put(ctx, key, file.File, "image/"+file.ContentType); err != nil {
+				return moved, err
+			}
+			if err := userRepo.MoveImageFileToStorage(ctx, file, key); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+}
+
+// imageURL строит ссылку на аватар для DTO. Хеш есть, только если изображение загружено вместе с пользователем.
+func (u *User) imageURL(user *model.User) string {
+	var hash []byte
+	if user.Image != nil {
+		hash = user.Image.Hash
+	}
+
+	return u.imageURLs.UserImageURL(user.ID, user.ImageId, hash, time.Now())
+}
+
+// CheckImageURL проверяет подпись ссылки на аватар, если подпись включена.
+func (u *User) CheckImageURL(userId int, query url.Values) error {
+	if err := u.imageURLs.Verify(userId, query, time.Now()); err != nil {
+		return tracerr.Errorf("%w: %v", repo.ErrUnauthorized, err)
+	}
+
+	return nil
+}
+
+// ImageURLSigned сообщает, что ссылки на аватары подписываются и имеют срок действия.
+func (u *User) ImageURLSigned() bool {
+	return u.imageURLs.Signed()
+}
+
+func (u *User) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		_ = u.blobStore.Delete(ctx, key)
+	}
+}
+
+func imageBlobKey(hash []byte, nonce, name, format string) string {
+	return fmt.Sprintf("images/%x/%s/%s.%s", hash, nonce, name, format)
+}
+
+// getHash вычисляет адрес изображения для дедупликации.
+// Нужна криптостойкая функция: под подобранную коллизию можно было бы подменить чужой аватар.
+func getHash(file []byte) []byte {
+	sum := sha256.Sum256(file)
+	return sum[:]
+}
@@ -0,0 +1,38 @@
+CREATE TYPE report_reason_enum AS ENUM ('CHEATING', 'HARASSMENT', 'HATE_SPEECH', 'SPAM', 'INAPPROPRIATE_NAME', 'OTHER');
+CREATE TYPE report_status_enum AS ENUM ('OPEN', 'RESOLVED');
+CREATE TYPE moderation_action_enum AS ENUM ('WARN', 'BAN', 'DISMISS');
+
+ALTER TABLE users
+    ADD COLUMN banned_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS reports
+(
+    id               BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    reporter_id      BIGINT             NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reported_user_id BIGINT             NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    game_room_id     TEXT               NOT NULL,
+    reason           report_reason_enum NOT NULL,
+    comment          TEXT               NOT NULL DEFAULT '',
+    status           report_status_enum NOT NULL DEFAULT 'OPEN',
+    resolution       moderation_action_enum,
+    created_at       TIMESTAMPTZ        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at      TIMESTAMPTZ,
+    CONSTRAINT unique_reports_room UNIQUE (reporter_id, reported_user_id, game_room_id),
+    CHECK (reporter_id <> reported_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS reports_status_idx ON reports (status, created_at);
+CREATE INDEX IF NOT EXISTS reports_reported_user_idx ON reports (reported_user_id);
+
+CREATE TABLE IF NOT EXISTS moderation_actions
+(
+    id           BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    report_id    BIGINT                 REFERENCES reports (id) ON DELETE SET NULL,
+    user_id      BIGINT                 NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    moderator_id BIGINT                 NOT NULL REFERENCES users (id),
+    action       moderation_action_enum NOT NULL,
+    comment      TEXT                   NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ            NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS moderation_actions_user_idx ON moderation_actions (user_id, created_at);
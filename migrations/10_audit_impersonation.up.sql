@@ -0,0 +1,4 @@
+ALTER TABLE audit_events
+    ADD COLUMN impersonator_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS audit_events_impersonator_idx ON audit_events (impersonator_id, id) WHERE impersonator_id IS NOT NULL;
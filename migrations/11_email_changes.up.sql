@@ -0,0 +1,22 @@
+-- Смена почты больше не должна ломать коды восстановления
+ALTER TABLE recovery_codes
+    DROP CONSTRAINT IF EXISTS recovery_codes_email_fkey,
+    ADD CONSTRAINT recovery_codes_email_fkey FOREIGN KEY (email) REFERENCES users (email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS email_changes
+(
+    id                BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id           BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    old_email         TEXT        NOT NULL,
+    new_email         TEXT        NOT NULL,
+    code              VARCHAR(6)  NOT NULL,
+    revert_token      TEXT        NOT NULL UNIQUE,
+    expired_at        TIMESTAMPTZ NOT NULL,
+    revert_expired_at TIMESTAMPTZ,
+    confirmed_at      TIMESTAMPTZ,
+    reverted_at       TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- У пользователя может быть только одна неподтверждённая смена почты
+CREATE UNIQUE INDEX IF NOT EXISTS email_changes_pending_idx ON email_changes (user_id) WHERE confirmed_at IS NULL;
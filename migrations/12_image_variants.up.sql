@@ -0,0 +1,9 @@
+CREATE TABLE image_variants
+(
+    image_id     BIGINT      NOT NULL REFERENCES images (id) ON DELETE CASCADE,
+    size         INT         NOT NULL,
+    content_type TEXT        NOT NULL,
+    file         BYTEA       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (image_id, size, content_type)
+);
@@ -0,0 +1,21 @@
+-- Перепривязываем пользователей к самой старой копии каждого изображения и удаляем дубликаты
+UPDATE users u
+SET image_id = d.keep_id
+FROM (SELECT id, min(id) OVER (PARTITION BY hash) AS keep_id FROM images) d
+WHERE u.image_id = d.id
+  AND d.id <> d.keep_id;
+
+DELETE
+FROM images i
+    USING images k
+WHERE i.hash = k.hash
+  AND i.id > k.id;
+
+ALTER TABLE images
+    ADD COLUMN ref_count INT NOT NULL DEFAULT 0;
+
+UPDATE images i
+SET ref_count = (SELECT count(*) FROM users u WHERE u.image_id = i.id);
+
+CREATE UNIQUE INDEX images_hash_uindex ON images (hash);
+CREATE INDEX images_unreferenced_index ON images (updated_at) WHERE ref_count <= 0;
@@ -0,0 +1,18 @@
+CREATE TABLE blobs
+(
+    key          TEXT PRIMARY KEY,
+    content_type TEXT        NOT NULL,
+    data         BYTEA       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Содержимое изображений переезжает в хранилище блобов, в таблицах остаётся только ключ
+ALTER TABLE images
+    ALTER COLUMN file DROP NOT NULL,
+    ADD COLUMN storage_key TEXT,
+    ADD CONSTRAINT images_file_check CHECK (file IS NOT NULL OR storage_key IS NOT NULL);
+
+ALTER TABLE image_variants
+    ALTER COLUMN file DROP NOT NULL,
+    ADD COLUMN storage_key TEXT,
+    ADD CONSTRAINT image_variants_file_check CHECK (file IS NOT NULL OR storage_key IS NOT NULL);
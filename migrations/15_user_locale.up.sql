@@ -0,0 +1,3 @@
+-- Язык, на котором пользователь получает письма
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT 'ru';
@@ -0,0 +1,19 @@
+-- Письма записываются в той же транзакции, что и изменения, из-за которых они отправляются,
+-- и доставляются фоновым воркером
+CREATE TABLE IF NOT EXISTS email_outbox
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    recipient       TEXT        NOT NULL,
+    subject         TEXT        NOT NULL,
+    text_body       TEXT        NOT NULL,
+    html_body       TEXT        NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'DEAD')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS email_outbox_pending_idx ON email_outbox (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS email_outbox_status_idx ON email_outbox (status, created_at DESC);
@@ -0,0 +1,13 @@
+-- Подписки пользователя на категории писем. Нет строки — категория включена
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    category   TEXT        NOT NULL,
+    enabled    BOOLEAN     NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, category)
+);
+
+ALTER TABLE email_outbox
+    ADD COLUMN IF NOT EXISTS category         TEXT NOT NULL DEFAULT 'SECURITY',
+    ADD COLUMN IF NOT EXISTS list_unsubscribe TEXT;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type       TEXT        NOT NULL,
+    payload    JSONB       NOT NULL DEFAULT '{}',
+    -- Одно и то же событие (например, повторный запрос завершения игры) не должно порождать дубли
+    dedup_key  TEXT        NOT NULL,
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, dedup_key)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Автор предложенной темы, чтобы сообщить ему о решении модератора
+ALTER TABLE topics
+    ADD COLUMN IF NOT EXISTS suggested_by BIGINT REFERENCES users (id) ON DELETE SET NULL;
+
+-- Ачивка выдаётся автоматически, когда число побед игрока достигает wins_required
+ALTER TABLE achievements
+    ADD COLUMN IF NOT EXISTS wins_required INT;
@@ -0,0 +1,10 @@
+-- Итог игры. Победитель есть только у WIN и FORFEIT: ничья, брошенная игра и неявка второго
+-- игрока записываются без него
+ALTER TABLE games
+    ADD COLUMN IF NOT EXISTS outcome TEXT CHECK (outcome IN ('WIN', 'DRAW', 'FORFEIT', 'ABANDONED', 'NO_SHOW'));
+
+UPDATE games SET outcome = 'WIN' WHERE winner_id IS NOT NULL AND outcome IS NULL;
+
+ALTER TABLE games
+    ADD CONSTRAINT games_outcome_winner_check
+        CHECK (outcome IS NULL OR (outcome IN ('WIN', 'FORFEIT')) = (winner_id IS NOT NULL));
@@ -0,0 +1,27 @@
+-- Состояние идущих игр, общее для всех экземпляров сервиса. Запросы к одной комнате выполняются
+-- по очереди под блокировкой её строки, а изменения расходятся между экземплярами через NOTIFY
+CREATE TABLE IF NOT EXISTS live_games
+(
+    id                     TEXT PRIMARY KEY,
+    first_player_id        BIGINT      NOT NULL,
+    second_player_id       BIGINT,
+    first_player_score     INT         NOT NULL DEFAULT 0,
+    second_player_score    INT         NOT NULL DEFAULT 0,
+    first_player_reported  BOOLEAN     NOT NULL DEFAULT FALSE,
+    second_player_reported BOOLEAN     NOT NULL DEFAULT FALSE,
+    first_request          TIMESTAMPTZ NOT NULL,
+    first_finish_request   TIMESTAMPTZ,
+    status                 TEXT        NOT NULL CHECK (status IN ('PENDING', 'STARTED', 'DECLINED', 'FINISHED')),
+    winner_id              BIGINT,
+    outcome                TEXT CHECK (outcome IN ('WIN', 'DRAW', 'FORFEIT', 'ABANDONED', 'NO_SHOW')),
+    awaiting_judge         BOOLEAN     NOT NULL DEFAULT FALSE,
+    start_at               TIMESTAMPTZ NOT NULL,
+    finish_at              TIMESTAMPTZ NOT NULL,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at             TIMESTAMPTZ,
+    declined_at            TIMESTAMPTZ,
+    finished_at            TIMESTAMPTZ
+);
+
+-- Фоновая задача ищет игры с истёкшим дедлайном и давно закончившиеся игры
+CREATE INDEX IF NOT EXISTS live_games_status_idx ON live_games (status, finish_at);
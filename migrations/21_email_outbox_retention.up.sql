@@ -0,0 +1,8 @@
+-- Письма с кодами и ссылками теряют смысл после expires_at: такие не отправляются и не
+-- возвращаются в очередь. Тела отправленных и недоставленных писем стираются по истечении
+-- срока хранения, чтобы коды и ссылки не лежали в базе открытым текстом
+ALTER TABLE email_outbox
+    ADD COLUMN IF NOT EXISTS expires_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS redacted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS email_outbox_unredacted_idx ON email_outbox (created_at) WHERE redacted_at IS NULL;
@@ -0,0 +1,10 @@
+-- Файлы удалённых изображений, которые ещё предстоит удалить из хранилища блобов. Ключи
+-- записываются в том же запросе, что удаляет изображения, и остаются здесь, пока хранилище
+-- не подтвердит удаление
+CREATE TABLE IF NOT EXISTS blob_deletions
+(
+    storage_key TEXT PRIMARY KEY,
+    attempts    INT         NOT NULL DEFAULT 0,
+    last_error  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+-- Изображения, которые пользователь загружал или ставил себе. Выбрать аватар по id можно
+-- только из них
+CREATE TABLE IF NOT EXISTS user_images
+(
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    image_id   BIGINT      NOT NULL REFERENCES images (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, image_id)
+);
+
+INSERT INTO user_images (user_id, image_id)
+SELECT id, image_id
+FROM users
+WHERE image_id IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- Хеш изображения теперь SHA-256 вместо murmur3. Пересчитываем его у уже сохранённых изображений,
+-- иначе повторная загрузка того же файла не найдёт их и создаст копию
+UPDATE images
+SET hash = sha256(file)
+WHERE file IS NOT NULL;
+
+UPDATE images i
+SET hash = sha256(b.data)
+FROM blobs b
+WHERE i.file IS NULL
+  AND b.key = i.storage_key;